@@ -1,28 +1,37 @@
 package main
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
 	"fmt"
 	"log"
+	"os"
+	"slices"
+	"strconv"
 	"time"
 
 	sbom "github.com/CodeClarityCE/plugin-sbom-javascript/src/types/sbom/js"
 	plugin "github.com/CodeClarityCE/plugin-sca-license/src"
+	analysisConfig "github.com/CodeClarityCE/plugin-sca-license/src/config"
+	exceptionManager "github.com/CodeClarityCE/plugin-sca-license/src/exceptionManager"
+	"github.com/CodeClarityCE/plugin-sca-license/src/licenseCache"
 	"github.com/CodeClarityCE/plugin-sca-license/src/outputGenerator"
 	"github.com/CodeClarityCE/plugin-sca-license/src/types"
+	"github.com/CodeClarityCE/plugin-sca-license/src/workerPool"
 	"github.com/CodeClarityCE/utility-boilerplates"
 	types_amqp "github.com/CodeClarityCE/utility-types/amqp"
 	codeclarity "github.com/CodeClarityCE/utility-types/codeclarity_db"
 	"github.com/CodeClarityCE/utility-types/exceptions"
-	exceptionManager "github.com/CodeClarityCE/utility-types/exceptions"
-	knowledge "github.com/CodeClarityCE/utility-types/knowledge_db"
 	plugin_db "github.com/CodeClarityCE/utility-types/plugin_db"
 	"github.com/google/uuid"
 )
 
 // LicenseAnalysisHandler implements the AnalysisHandler interface
-type LicenseAnalysisHandler struct{}
+type LicenseAnalysisHandler struct {
+	// cache holds the licenses declared by dependencies across the analyses handled by the plugin
+	cache *licenseCache.LicenseCache
+}
 
 // StartAnalysis implements the AnalysisHandler interface
 func (h *LicenseAnalysisHandler) StartAnalysis(
@@ -31,7 +40,7 @@ func (h *LicenseAnalysisHandler) StartAnalysis(
 	config plugin_db.Plugin,
 	analysisDoc codeclarity.Analysis,
 ) (map[string]any, codeclarity.AnalysisStatus, error) {
-	return startAnalysis(databases, dispatcherMessage, config, analysisDoc)
+	return startAnalysis(databases, dispatcherMessage, config, analysisDoc, h.cache)
 }
 
 // main is the entry point of the program.
@@ -43,23 +52,44 @@ func main() {
 	defer pluginBase.Close()
 
 	// Start the plugin with our analysis handler
-	handler := &LicenseAnalysisHandler{}
+	handler := &LicenseAnalysisHandler{cache: newLicenseCache()}
 	err = pluginBase.Listen(handler)
 	if err != nil {
 		log.Fatalf("Failed to start plugin: %v", err)
 	}
 }
 
-func startAnalysis(databases *boilerplates.PluginDatabases, dispatcherMessage types_amqp.DispatcherPluginMessage, config plugin_db.Plugin, analysis_document codeclarity.Analysis) (map[string]any, codeclarity.AnalysisStatus, error) {
+// newLicenseCache creates the license cache of the plugin.
+// Its size and time to live can be set with the LICENSE_CACHE_SIZE and LICENSE_CACHE_TTL (e.g. "12h") environment variables.
+func newLicenseCache() *licenseCache.LicenseCache {
+	capacity := licenseCache.DEFAULT_CAPACITY
+	if value := os.Getenv("LICENSE_CACHE_SIZE"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			log.Printf("Invalid LICENSE_CACHE_SIZE %q, using %d", value, capacity)
+		} else {
+			capacity = parsed
+		}
+	}
+
+	ttl := licenseCache.DEFAULT_TTL
+	if value := os.Getenv("LICENSE_CACHE_TTL"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed <= 0 {
+			log.Printf("Invalid LICENSE_CACHE_TTL %q, using %s", value, ttl)
+		} else {
+			ttl = parsed
+		}
+	}
+
+	return licenseCache.NewLicenseCache(capacity, ttl)
+}
+
+func startAnalysis(databases *boilerplates.PluginDatabases, dispatcherMessage types_amqp.DispatcherPluginMessage, config plugin_db.Plugin, analysis_document codeclarity.Analysis, cache *licenseCache.LicenseCache) (map[string]any, codeclarity.AnalysisStatus, error) {
 	// Get analysis config
 	messageData := analysis_document.Config[config.Name].(map[string]any)
 	// Prepare the arguments for the plugin
-	licensePolicy := knowledge.LicensePolicy{}
-	if messageData["licensePolicy"] != nil {
-		for _, license := range messageData["licensePolicy"].([]interface{}) {
-			licensePolicy.DisallowedLicense = append(licensePolicy.DisallowedLicense, license.(string))
-		}
-	}
+	licenseConfig, configErr := analysisConfig.Parse(messageData)
 
 	// Get previous stage
 	analysis_stage := analysis_document.Stage - 1
@@ -97,52 +127,84 @@ func startAnalysis(databases *boilerplates.PluginDatabases, dispatcherMessage ty
 	var licenseOutput types.Output
 	var err error
 	start := time.Now()
-
-	// If no SBOMs were found, return success with empty results
-	if len(sbomKeys) == 0 {
+	// Errors are collected per analysis, so that concurrent and later analyses of the process do not report them
+	analysisErrors := exceptionManager.NewErrors()
+
+	if configErr != nil {
+		// An invalid configuration must not silently fall back to an analysis without policy
+		analysisErrors.AddError(
+			"The license analysis configuration is invalid", exceptions.GENERIC_ERROR,
+			configErr.Error(), exceptions.GENERIC_ERROR,
+		)
+		licenseOutput = outputGenerator.FailureOutput(sbom.AnalysisInfo{Status: codeclarity.FAILURE}, analysisErrors, start)
+	} else if len(sbomKeys) == 0 {
+		// If no SBOMs were found, return success with empty results
 		licenseOutput = outputGenerator.SuccessOutput(map[string]types.WorkSpaceLicenseInfo{}, types.AnalysisStats{}, sbom.AnalysisInfo{
 			Status: codeclarity.SUCCESS,
-		}, start)
+		}, analysisErrors, start)
 	} else {
-		// Process ALL available SBOMs and merge their results
-		mergedWorkspaces := make(map[string]types.WorkSpaceLicenseInfo)
-		mergedStats := types.AnalysisStats{}
-		hasErrors := false
-
-		for _, sbomInfo := range sbomKeys {
+		// Process ALL available SBOMs concurrently, every worker writes the output of its SBOM at the index of the SBOM
+		individualOutputs := make([]*types.Output, len(sbomKeys))
+		failed := make([]bool, len(sbomKeys))
+		sbomErrors := make([][]exceptions.Error, len(sbomKeys))
+
+		// The concurrency budget is split between the SBOMs and their workspaces, so that the configured bound holds across both levels
+		sbomConcurrency, workspaceConcurrency := workerPool.Split(licenseConfig.Concurrency, len(sbomKeys))
+		sbomConfig := licenseConfig
+		sbomConfig.Concurrency = workspaceConcurrency
+
+		workerPool.Run(len(sbomKeys), sbomConcurrency, func(index int) {
+			sbomInfo := sbomKeys[index]
 			log.Printf("Processing %s SBOM for license analysis", sbomInfo.language)
 
 			res := codeclarity.Result{
 				Id: sbomInfo.id,
 			}
-			err = databases.Codeclarity.NewSelect().Model(&res).Where("id = ?", sbomInfo.id).Scan(context.Background())
+			err := databases.Codeclarity.NewSelect().Model(&res).Where("id = ?", sbomInfo.id).Scan(context.Background())
 			if err != nil {
 				log.Printf("Failed to retrieve %s SBOM: %v", sbomInfo.language, err)
-				continue
+				return
 			}
 
 			sbomData := sbom.Output{}
 			err = json.Unmarshal(res.Result.([]byte), &sbomData)
 			if err != nil {
 				log.Printf("Failed to unmarshal %s SBOM: %v", sbomInfo.language, err)
-				exceptionManager.AddError(
+				analysisErrors.AddError(
 					"", exceptions.GENERIC_ERROR,
 					fmt.Sprintf("Error when reading %s output: %s", sbomInfo.pluginName, err), exceptions.FAILED_TO_READ_PREVIOUS_STAGE_OUTPUT,
 				)
-				hasErrors = true
-				continue
+				failed[index] = true
+				return
 			}
 
 			// Process this SBOM
-			individualOutput := plugin.Start(databases.Knowledge, sbomData, sbomInfo.language, licensePolicy, start)
+			individualOutput := plugin.Start(databases.Knowledge, sbomData, sbomInfo.language, sbomConfig, cache, start)
+			sbomErrors[index] = individualOutput.AnalysisInfo.Errors
 
 			if individualOutput.AnalysisInfo.Status != codeclarity.SUCCESS {
 				log.Printf("%s license analysis failed", sbomInfo.language)
-				hasErrors = true
-				continue
+				failed[index] = true
+				return
 			}
 
 			log.Printf("Successfully processed %s license analysis with %d workspaces", sbomInfo.language, len(individualOutput.WorkSpaces))
+			individualOutputs[index] = &individualOutput
+		})
+
+		// Outputs are merged in the order of the SBOMs, not in the order they completed in, to keep the result deterministic
+		mergedWorkspaces := make(map[string]types.WorkSpaceLicenseInfo)
+		mergedStats := types.AnalysisStats{}
+		mergedCacheStats := types.CacheStats{}
+		hasErrors := slices.Contains(failed, true)
+		for _, errs := range sbomErrors {
+			analysisErrors.Append(errs...)
+		}
+
+		for _, individualOutput := range individualOutputs {
+			if individualOutput == nil {
+				continue
+			}
 
 			// Merge the workspaces from this SBOM into the combined result
 			for workspaceKey, workspaceData := range individualOutput.WorkSpaces {
@@ -163,6 +225,7 @@ func startAnalysis(databases *boilerplates.PluginDatabases, dispatcherMessage ty
 							for dep := range combined {
 								mergedDeps = append(mergedDeps, dep)
 							}
+							slices.Sort(mergedDeps)
 							existing.LicensesDepMap[licenseId] = mergedDeps
 						} else {
 							existing.LicensesDepMap[licenseId] = deps
@@ -185,6 +248,7 @@ func startAnalysis(databases *boilerplates.PluginDatabases, dispatcherMessage ty
 							for dep := range combined {
 								mergedDeps = append(mergedDeps, dep)
 							}
+							slices.Sort(mergedDeps)
 							existing.NonSpdxLicensesDepMap[licenseId] = mergedDeps
 						} else {
 							existing.NonSpdxLicensesDepMap[licenseId] = deps
@@ -204,8 +268,43 @@ func startAnalysis(databases *boilerplates.PluginDatabases, dispatcherMessage ty
 					for violation := range violationSet {
 						mergedViolations = append(mergedViolations, violation)
 					}
+					slices.Sort(mergedViolations)
 					existing.LicenseComplianceViolations = mergedViolations
 
+					// Merge proprietary dependencies (avoiding duplicates)
+					proprietarySet := make(map[string]bool)
+					for _, dep := range existing.ProprietaryDependencies {
+						proprietarySet[dep] = true
+					}
+					for _, dep := range workspaceData.ProprietaryDependencies {
+						proprietarySet[dep] = true
+					}
+
+					mergedProprietary := []string{}
+					for dep := range proprietarySet {
+						mergedProprietary = append(mergedProprietary, dep)
+					}
+					slices.Sort(mergedProprietary)
+					existing.ProprietaryDependencies = mergedProprietary
+
+					// Merge policy violations, sorted by dependency then license
+					existing.PolicyViolations = append(existing.PolicyViolations, workspaceData.PolicyViolations...)
+					slices.SortStableFunc(existing.PolicyViolations, func(a, b types.PolicyViolation) int {
+						return cmp.Or(cmp.Compare(a.Dependency, b.Dependency), cmp.Compare(a.License, b.License))
+					})
+
+					// Merge license incompatibilities, sorted by dependency then license
+					existing.LicenseIncompatibilities = append(existing.LicenseIncompatibilities, workspaceData.LicenseIncompatibilities...)
+					slices.SortStableFunc(existing.LicenseIncompatibilities, func(a, b types.LicenseIncompatibility) int {
+						return cmp.Or(cmp.Compare(a.Dependency, b.Dependency), cmp.Compare(a.License, b.License))
+					})
+
+					// The project license is the same for every SBOM unless one of them does not declare it
+					if existing.ProjectLicense == "" {
+						existing.ProjectLicense = workspaceData.ProjectLicense
+						existing.ProjectLicenseSource = workspaceData.ProjectLicenseSource
+					}
+
 					// Merge dependency info
 					for depKey, depInfo := range workspaceData.DependencyInfo {
 						existing.DependencyInfo[depKey] = depInfo
@@ -223,6 +322,14 @@ func startAnalysis(databases *boilerplates.PluginDatabases, dispatcherMessage ty
 			mergedStats.NumberOfNonSpdxLicenses += individualOutput.AnalysisInfo.AnalysisStats.NumberOfNonSpdxLicenses
 			mergedStats.NumberOfCopyLeftLicenses += individualOutput.AnalysisInfo.AnalysisStats.NumberOfCopyLeftLicenses
 			mergedStats.NumberOfPermissiveLicenses += individualOutput.AnalysisInfo.AnalysisStats.NumberOfPermissiveLicenses
+			mergedStats.NumberOfLookupErrors += individualOutput.AnalysisInfo.AnalysisStats.NumberOfLookupErrors
+			mergedStats.NumberOfProprietaryDeps += individualOutput.AnalysisInfo.AnalysisStats.NumberOfProprietaryDeps
+			mergedStats.NumberOfWaivedViolations += individualOutput.AnalysisInfo.AnalysisStats.NumberOfWaivedViolations
+
+			// Merge cache statistics, the cache is shared so its size is the largest one observed
+			mergedCacheStats.Hits += individualOutput.AnalysisInfo.CacheStats.Hits
+			mergedCacheStats.Misses += individualOutput.AnalysisInfo.CacheStats.Misses
+			mergedCacheStats.Entries = max(mergedCacheStats.Entries, individualOutput.AnalysisInfo.CacheStats.Entries)
 
 			// Merge license distribution maps
 			for licenseType, count := range individualOutput.AnalysisInfo.AnalysisStats.LicenseDist {
@@ -232,19 +339,43 @@ func startAnalysis(databases *boilerplates.PluginDatabases, dispatcherMessage ty
 				mergedStats.LicenseDist[licenseType] += count
 			}
 
-			// Individual output processed successfully - stats already merged above
+			// Merge policy violation distribution maps
+			for severity, count := range individualOutput.AnalysisInfo.AnalysisStats.PolicyViolationDist {
+				if mergedStats.PolicyViolationDist == nil {
+					mergedStats.PolicyViolationDist = make(map[string]int)
+				}
+				mergedStats.PolicyViolationDist[severity] += count
+			}
+
+			// Merge license incompatibility distribution maps
+			for severity, count := range individualOutput.AnalysisInfo.AnalysisStats.LicenseIncompatibilityDist {
+				if mergedStats.LicenseIncompatibilityDist == nil {
+					mergedStats.LicenseIncompatibilityDist = make(map[string]int)
+				}
+				mergedStats.LicenseIncompatibilityDist[severity] += count
+			}
+
+			// Merge license category distribution maps
+			for category, count := range individualOutput.AnalysisInfo.AnalysisStats.LicenseCategoryDist {
+				if mergedStats.LicenseCategoryDist == nil {
+					mergedStats.LicenseCategoryDist = make(map[string]int)
+				}
+				mergedStats.LicenseCategoryDist[category] += count
+			}
+
 		}
 
 		if hasErrors && len(mergedWorkspaces) == 0 {
 			// If all SBOM processing failed, return failure
 			sbomAnalysisInfo := sbom.AnalysisInfo{Status: codeclarity.FAILURE}
-			licenseOutput = outputGenerator.FailureOutput(sbomAnalysisInfo, start)
+			licenseOutput = outputGenerator.FailureOutput(sbomAnalysisInfo, analysisErrors, start)
 		} else {
 			// Return merged results with empty sbom.AnalysisInfo for compatibility
 			sbomAnalysisInfo := sbom.AnalysisInfo{Status: codeclarity.SUCCESS}
 
 			log.Printf("License analysis completed: merged %d workspaces from %d SBOMs", len(mergedWorkspaces), len(sbomKeys))
-			licenseOutput = outputGenerator.SuccessOutput(mergedWorkspaces, mergedStats, sbomAnalysisInfo, start)
+			licenseOutput = outputGenerator.SuccessOutput(mergedWorkspaces, mergedStats, sbomAnalysisInfo, analysisErrors, start)
+			licenseOutput.AnalysisInfo.CacheStats = mergedCacheStats
 		}
 	}
 
@@ -0,0 +1,159 @@
+package classification
+
+// licenseCategories maps license families, as returned by Family, onto their category.
+// Licenses missing from this table are classified using familyPrefixes.
+var licenseCategories = map[string]Category{
+	// Public domain dedications
+	"CC0-1.0":    CATEGORY_PUBLIC_DOMAIN,
+	"Unlicense":  CATEGORY_PUBLIC_DOMAIN,
+	"PDDL-1.0":   CATEGORY_PUBLIC_DOMAIN,
+	"SAX-PD":     CATEGORY_PUBLIC_DOMAIN,
+	"blessing":   CATEGORY_PUBLIC_DOMAIN,
+	"WTFPL":      CATEGORY_PUBLIC_DOMAIN,
+	"CC-PDDC":    CATEGORY_PUBLIC_DOMAIN,
+	"NIST-PD":    CATEGORY_PUBLIC_DOMAIN,
+	"libselinux": CATEGORY_PUBLIC_DOMAIN,
+
+	// Permissive licenses
+	"0BSD":                           CATEGORY_PERMISSIVE,
+	"MIT":                            CATEGORY_PERMISSIVE,
+	"MIT-0":                          CATEGORY_PERMISSIVE,
+	"ISC":                            CATEGORY_PERMISSIVE,
+	"X11":                            CATEGORY_PERMISSIVE,
+	"Zlib":                           CATEGORY_PERMISSIVE,
+	"zlib-acknowledgement":           CATEGORY_PERMISSIVE,
+	"BSL-1.0":                        CATEGORY_PERMISSIVE,
+	"NCSA":                           CATEGORY_PERMISSIVE,
+	"PostgreSQL":                     CATEGORY_PERMISSIVE,
+	"Python-2.0":                     CATEGORY_PERMISSIVE,
+	"PSF-2.0":                        CATEGORY_PERMISSIVE,
+	"Artistic-2.0":                   CATEGORY_PERMISSIVE,
+	"BlueOak-1.0.0":                  CATEGORY_PERMISSIVE,
+	"UPL-1.0":                        CATEGORY_PERMISSIVE,
+	"Unicode-DFS-2015":               CATEGORY_PERMISSIVE,
+	"Unicode-DFS-2016":               CATEGORY_PERMISSIVE,
+	"Unicode-3.0":                    CATEGORY_PERMISSIVE,
+	"ICU":                            CATEGORY_PERMISSIVE,
+	"W3C":                            CATEGORY_PERMISSIVE,
+	"curl":                           CATEGORY_PERMISSIVE,
+	"OpenSSL":                        CATEGORY_PERMISSIVE,
+	"Libpng":                         CATEGORY_PERMISSIVE,
+	"libpng-2.0":                     CATEGORY_PERMISSIVE,
+	"HPND":                           CATEGORY_PERMISSIVE,
+	"FTL":                            CATEGORY_PERMISSIVE,
+	"MirOS":                          CATEGORY_PERMISSIVE,
+	"Beerware":                       CATEGORY_PERMISSIVE,
+	"JSON":                           CATEGORY_PERMISSIVE,
+	"PHP-3.0":                        CATEGORY_PERMISSIVE,
+	"PHP-3.01":                       CATEGORY_PERMISSIVE,
+	"Ruby":                           CATEGORY_PERMISSIVE,
+	"OFL-1.0":                        CATEGORY_PERMISSIVE,
+	"OFL-1.1":                        CATEGORY_PERMISSIVE,
+	"CECILL-B":                       CATEGORY_PERMISSIVE,
+	"MS-PL":                          CATEGORY_PERMISSIVE,
+	"Python-2.0.1":                   CATEGORY_PERMISSIVE,
+	"ECL-2.0":                        CATEGORY_PERMISSIVE,
+	"EFL-2.0":                        CATEGORY_PERMISSIVE,
+	"Zend-2.0":                       CATEGORY_PERMISSIVE,
+	"TCL":                            CATEGORY_PERMISSIVE,
+	"Vim":                            CATEGORY_PERMISSIVE,
+	"WTFNMFPL":                       CATEGORY_PERMISSIVE,
+	"Apache-2.0-with-LLVM-exception": CATEGORY_PERMISSIVE,
+
+	// Weak copyleft licenses, whose obligations apply to the licensed files or library only
+	"MPL-1.0":                          CATEGORY_WEAK_COPYLEFT,
+	"MPL-1.1":                          CATEGORY_WEAK_COPYLEFT,
+	"MPL-2.0":                          CATEGORY_WEAK_COPYLEFT,
+	"MPL-2.0-no-copyleft-exception":    CATEGORY_WEAK_COPYLEFT,
+	"EPL-1.0":                          CATEGORY_WEAK_COPYLEFT,
+	"EPL-2.0":                          CATEGORY_WEAK_COPYLEFT,
+	"CDDL-1.0":                         CATEGORY_WEAK_COPYLEFT,
+	"CDDL-1.1":                         CATEGORY_WEAK_COPYLEFT,
+	"CPL-1.0":                          CATEGORY_WEAK_COPYLEFT,
+	"IPL-1.0":                          CATEGORY_WEAK_COPYLEFT,
+	"APSL-2.0":                         CATEGORY_WEAK_COPYLEFT,
+	"MS-RL":                            CATEGORY_WEAK_COPYLEFT,
+	"CECILL-C":                         CATEGORY_WEAK_COPYLEFT,
+	"ODbL-1.0":                         CATEGORY_WEAK_COPYLEFT,
+	"Artistic-1.0":                     CATEGORY_WEAK_COPYLEFT,
+	"Artistic-1.0-Perl":                CATEGORY_WEAK_COPYLEFT,
+	"LGPLLR":                           CATEGORY_WEAK_COPYLEFT,
+	"GPL-2.0-with-classpath-exception": CATEGORY_WEAK_COPYLEFT,
+
+	// Strong copyleft licenses, whose obligations extend to the whole derivative work
+	"GPL-1.0":    CATEGORY_STRONG_COPYLEFT,
+	"GPL-2.0":    CATEGORY_STRONG_COPYLEFT,
+	"GPL-3.0":    CATEGORY_STRONG_COPYLEFT,
+	"EUPL-1.0":   CATEGORY_STRONG_COPYLEFT,
+	"EUPL-1.1":   CATEGORY_STRONG_COPYLEFT,
+	"EUPL-1.2":   CATEGORY_STRONG_COPYLEFT,
+	"CECILL-1.0": CATEGORY_STRONG_COPYLEFT,
+	"CECILL-1.1": CATEGORY_STRONG_COPYLEFT,
+	"CECILL-2.0": CATEGORY_STRONG_COPYLEFT,
+	"CECILL-2.1": CATEGORY_STRONG_COPYLEFT,
+	"Sleepycat":  CATEGORY_STRONG_COPYLEFT,
+	"QPL-1.0":    CATEGORY_STRONG_COPYLEFT,
+	"OSL-1.0":    CATEGORY_STRONG_COPYLEFT,
+	"OSL-2.0":    CATEGORY_STRONG_COPYLEFT,
+	"OSL-2.1":    CATEGORY_STRONG_COPYLEFT,
+
+	// Network copyleft licenses, whose obligations are also triggered by providing the software over a network
+	"AGPL-1.0": CATEGORY_NETWORK_COPYLEFT,
+	"AGPL-3.0": CATEGORY_NETWORK_COPYLEFT,
+	"OSL-3.0":  CATEGORY_NETWORK_COPYLEFT,
+	"RPL-1.1":  CATEGORY_NETWORK_COPYLEFT,
+	"RPL-1.5":  CATEGORY_NETWORK_COPYLEFT,
+	"CPAL-1.0": CATEGORY_NETWORK_COPYLEFT,
+
+	// Proprietary and source-available licenses
+	"BUSL-1.1":          CATEGORY_PROPRIETARY,
+	"SSPL-1.0":          CATEGORY_PROPRIETARY,
+	"Elastic-2.0":       CATEGORY_PROPRIETARY,
+	"Commons-Clause":    CATEGORY_PROPRIETARY,
+	LICENSE_PROPRIETARY: CATEGORY_PROPRIETARY,
+}
+
+// familyPrefixes classifies the licenses missing from licenseCategories by the prefix of their family.
+// Rules are tried in order, so that more specific prefixes must come first.
+var familyPrefixes = []struct {
+	prefix   string
+	category Category
+}{
+	{"AGPL-", CATEGORY_NETWORK_COPYLEFT},
+	{"LGPL-", CATEGORY_WEAK_COPYLEFT},
+	{"GPL-", CATEGORY_STRONG_COPYLEFT},
+	{"CC-BY-NC", CATEGORY_PROPRIETARY},
+	{"CC-BY-ND", CATEGORY_PROPRIETARY},
+	{"CC-BY-SA-", CATEGORY_STRONG_COPYLEFT},
+	{"CC-BY-", CATEGORY_PERMISSIVE},
+	{"PolyForm-", CATEGORY_PROPRIETARY},
+	{"MPL-", CATEGORY_WEAK_COPYLEFT},
+	{"EPL-", CATEGORY_WEAK_COPYLEFT},
+	{"CDDL-", CATEGORY_WEAK_COPYLEFT},
+	{"EUPL-", CATEGORY_STRONG_COPYLEFT},
+	{"BSD-", CATEGORY_PERMISSIVE},
+	{"MIT-", CATEGORY_PERMISSIVE},
+	{"Apache-", CATEGORY_PERMISSIVE},
+	{"AFL-", CATEGORY_PERMISSIVE},
+	{"Zlib", CATEGORY_PERMISSIVE},
+}
+
+// linkingExceptions lists, in lower case, the SPDX exceptions that allow works to link
+// to a strong copyleft library without being subject to its terms.
+var linkingExceptions = map[string]bool{
+	"classpath-exception-2.0":          true,
+	"gcc-exception-2.0":                true,
+	"gcc-exception-3.1":                true,
+	"llvm-exception":                   true,
+	"autoconf-exception-2.0":           true,
+	"autoconf-exception-3.0":           true,
+	"bison-exception-2.2":              true,
+	"font-exception-2.0":               true,
+	"gpl-3.0-linking-exception":        true,
+	"gpl-3.0-linking-source-exception": true,
+	"gpl-cc-1.0":                       true,
+	"libtool-exception":                true,
+	"openjdk-assembly-exception-1.0":   true,
+	"universal-foss-exception-1.0":     true,
+	"wxwindows-exception-3.1":          true,
+}
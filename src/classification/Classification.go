@@ -0,0 +1,98 @@
+package classification
+
+import (
+	"strings"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+)
+
+type Category string
+
+const (
+	CATEGORY_PERMISSIVE       Category = "permissive"
+	CATEGORY_WEAK_COPYLEFT    Category = "weak_copyleft"
+	CATEGORY_STRONG_COPYLEFT  Category = "strong_copyleft"
+	CATEGORY_NETWORK_COPYLEFT Category = "network_copyleft"
+	CATEGORY_PUBLIC_DOMAIN    Category = "public_domain"
+	// Proprietary and source-available licenses, which restrict use, modification or redistribution
+	CATEGORY_PROPRIETARY Category = "proprietary"
+	CATEGORY_UNKNOWN     Category = "unknown"
+)
+
+// LICENSE_PROPRIETARY is the license of the packages whose authors grant no right to use them,
+// such as npm packages declaring "UNLICENSED". It is not an SPDX license but its category is known.
+const LICENSE_PROPRIETARY = "LicenseRef-Proprietary"
+
+// Categories lists every category, from the least to the most restrictive.
+var Categories = []Category{
+	CATEGORY_PUBLIC_DOMAIN,
+	CATEGORY_PERMISSIVE,
+	CATEGORY_WEAK_COPYLEFT,
+	CATEGORY_STRONG_COPYLEFT,
+	CATEGORY_NETWORK_COPYLEFT,
+	CATEGORY_PROPRIETARY,
+	CATEGORY_UNKNOWN,
+}
+
+// IsCopyleft reports whether the category requires derivative works to be distributed under the same terms.
+func (c Category) IsCopyleft() bool {
+	return c == CATEGORY_WEAK_COPYLEFT || c == CATEGORY_STRONG_COPYLEFT || c == CATEGORY_NETWORK_COPYLEFT
+}
+
+// IsPermissive reports whether the category puts no copyleft obligation on derivative works.
+func (c Category) IsPermissive() bool {
+	return c == CATEGORY_PERMISSIVE || c == CATEGORY_PUBLIC_DOMAIN
+}
+
+// ParseCategory parses the name of a category, as used in the plugin configuration.
+func ParseCategory(name string) (Category, bool) {
+	normalized := Category(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "-", "_"))
+	for _, category := range Categories {
+		if category == normalized {
+			return category, true
+		}
+	}
+	return CATEGORY_UNKNOWN, false
+}
+
+// Classify returns the category of an SPDX license identifier.
+// The "-only" and "-or-later" variants of a license, as well as its "+" form, share the category of the license.
+// Licenses are looked up in the category table first and in the family prefixes second.
+// The remaining licenses are classified from the SPDX license list, once loaded (see LoadSPDXLicenses):
+// the copyleft licenses are covered by the table and the prefixes, so the other OSI approved or FSF libre licenses are permissive.
+func Classify(licenseId string) Category {
+	family := Family(licenseId)
+
+	if category, ok := licenseCategories[family]; ok {
+		return category
+	}
+	for _, rule := range familyPrefixes {
+		if strings.HasPrefix(family, rule.prefix) {
+			return rule.category
+		}
+	}
+	if isFreeLicense(family) {
+		return CATEGORY_PERMISSIVE
+	}
+	return CATEGORY_UNKNOWN
+}
+
+// ClassifyLicense returns the category of a single license of an expression.
+// Some exceptions lift the copyleft obligations of a license on the works that link to it,
+// such as GPL-2.0-only WITH Classpath-exception-2.0, which is then considered a weak copyleft license.
+func ClassifyLicense(license spdx.Expression) Category {
+	category := Classify(license.License)
+	if license.Exception != "" && category == CATEGORY_STRONG_COPYLEFT && linkingExceptions[strings.ToLower(license.Exception)] {
+		return CATEGORY_WEAK_COPYLEFT
+	}
+	return category
+}
+
+// Family strips the version range suffixes of an SPDX license identifier,
+// so that GPL-2.0-only, GPL-2.0-or-later, GPL-2.0+ and GPL-2.0 share the GPL-2.0 family.
+func Family(licenseId string) string {
+	family := strings.TrimSuffix(licenseId, "+")
+	family = strings.TrimSuffix(family, "-only")
+	family = strings.TrimSuffix(family, "-or-later")
+	return family
+}
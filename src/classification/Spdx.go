@@ -0,0 +1,29 @@
+package classification
+
+import (
+	"sync/atomic"
+
+	knowledge "github.com/CodeClarityCE/utility-types/knowledge_db"
+)
+
+// freeLicenses holds the families of the licenses the SPDX license list flags as OSI approved or FSF libre.
+// It is replaced as a whole when the SPDX licenses are loaded, so that concurrent analyses read a consistent set.
+var freeLicenses atomic.Pointer[map[string]bool]
+
+// LoadSPDXLicenses records which licenses of the SPDX license list of the knowledge base are free software licenses,
+// which classifies the licenses missing from the category table (see Classify).
+func LoadSPDXLicenses(licenses []knowledge.License) {
+	free := map[string]bool{}
+	for _, license := range licenses {
+		if license.Details.IsOsiApproved || license.Details.IsFsfLibre {
+			free[Family(license.LicenseID)] = true
+		}
+	}
+	freeLicenses.Store(&free)
+}
+
+// isFreeLicense reports whether the SPDX license list flags a license family as OSI approved or FSF libre.
+func isFreeLicense(family string) bool {
+	free := freeLicenses.Load()
+	return free != nil && (*free)[family]
+}
@@ -0,0 +1,220 @@
+package compatibility
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/classification"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+)
+
+// Compatibility tells whether code under an inbound license (a dependency) can be distributed as part of a work under an outbound license (the project).
+type Compatibility string
+
+const (
+	COMPATIBLE Compatibility = "compatible"
+	// The dependency can be used if its own terms keep applying to it, e.g. weak copyleft files distributed unmodified or linked dynamically
+	CONDITIONAL Compatibility = "conditional"
+	// One of the licenses is unknown or proprietary, the combination needs a review
+	UNKNOWN      Compatibility = "unknown"
+	INCOMPATIBLE Compatibility = "incompatible"
+)
+
+// rank orders compatibilities from the worst to the best
+var rank = map[Compatibility]int{
+	INCOMPATIBLE: 0,
+	UNKNOWN:      1,
+	CONDITIONAL:  2,
+	COMPATIBLE:   3,
+}
+
+// Verdict is the compatibility of a dependency license with the project license, along with the reason for it.
+type Verdict struct {
+	Compatibility Compatibility `json:"compatibility"`
+	Reason        string        `json:"reason"`
+	// Inbound and Outbound are the licenses of the dependency and of the project the verdict is about
+	Inbound  string `json:"inbound"`
+	Outbound string `json:"outbound"`
+}
+
+// Check checks whether a dependency under the inbound expression can be part of a project under the outbound expression.
+// known tells whether a license identifier of the inbound expression is an SPDX license, the others are unknown.
+// The dependency may be used under any alternative of a disjunction, but must comply with every term of a conjunction.
+// A project under a disjunction may be redistributed under any of its alternatives, so the dependency must be compatible with all of them.
+func Check(inbound spdx.Expression, outbound spdx.Expression, known func(licenseId string) bool) Verdict {
+	if inbound.IsEmpty() || outbound.IsEmpty() {
+		return Verdict{Compatibility: UNKNOWN, Reason: "the license of the dependency or of the project is unknown", Inbound: inbound.String(), Outbound: outbound.String()}
+	}
+
+	if !inbound.IsLeaf() {
+		verdicts := []Verdict{}
+		for _, term := range inbound.Terms {
+			verdicts = append(verdicts, Check(term, outbound, known))
+		}
+		if inbound.Operator == spdx.OPERATOR_OR {
+			return best(verdicts)
+		}
+		return worst(verdicts)
+	}
+
+	verdicts := []Verdict{}
+	for _, outboundLicense := range outbound.Leaves() {
+		if !known(inbound.License) {
+			verdicts = append(verdicts, Verdict{Compatibility: UNKNOWN, Reason: inbound.License + " is not an SPDX license"})
+		} else {
+			verdicts = append(verdicts, CheckLicense(inbound, outboundLicense))
+		}
+		verdicts[len(verdicts)-1].Inbound, verdicts[len(verdicts)-1].Outbound = inbound.String(), outboundLicense.String()
+	}
+	return worst(verdicts)
+}
+
+// CheckLicense checks whether a dependency under the inbound license can be part of a project under the outbound license.
+// Both licenses are leaves of SPDX expressions, along with their "+" operator and exception.
+// An exception only adds permissions, so an inbound license with an exception is at least as compatible as its base license.
+func CheckLicense(inbound spdx.Expression, outbound spdx.Expression) Verdict {
+	verdict := checkLicense(inbound, outbound)
+	if inbound.Exception == "" {
+		return verdict
+	}
+	base := checkLicense(spdx.Expression{License: inbound.License, OrLater: inbound.OrLater}, outbound)
+	base.Inbound = inbound.String()
+	return best([]Verdict{verdict, base})
+}
+
+// checkLicense checks the compatibility of a single inbound license, exception included, with a single outbound license.
+func checkLicense(inbound spdx.Expression, outbound spdx.Expression) Verdict {
+	in, out := parseLicense(inbound), parseLicense(outbound)
+	verdict := func(compatibility Compatibility, reason string) Verdict {
+		return Verdict{Compatibility: compatibility, Reason: reason, Inbound: inbound.String(), Outbound: outbound.String()}
+	}
+
+	if inbound.License == outbound.License && inbound.Exception == outbound.Exception {
+		return verdict(COMPATIBLE, "the dependency and the project share the same license")
+	}
+
+	switch in.category {
+	case classification.CATEGORY_UNKNOWN, classification.CATEGORY_PROPRIETARY:
+		return verdict(UNKNOWN, inbound.String()+" is not an open source license, its terms need a review")
+	case classification.CATEGORY_PUBLIC_DOMAIN:
+		return verdict(COMPATIBLE, inbound.String()+" places no condition on redistribution")
+	case classification.CATEGORY_PERMISSIVE:
+		if in.family == "Apache" && out.gnu && out.family != "AGPL" && out.version < 3 && !out.orLater {
+			return verdict(INCOMPATIBLE, "the patent and indemnity terms of "+inbound.String()+" are additional restrictions that "+outbound.String()+" does not allow")
+		}
+		return verdict(COMPATIBLE, inbound.String()+" is permissive, it only requires its notices to be kept")
+	}
+
+	if out.category == classification.CATEGORY_UNKNOWN {
+		return verdict(UNKNOWN, "the compatibility of "+inbound.String()+" with "+outbound.String()+" is unknown")
+	}
+
+	switch in.category {
+	case classification.CATEGORY_WEAK_COPYLEFT:
+		if !out.category.IsCopyleft() || out.category == classification.CATEGORY_WEAK_COPYLEFT {
+			return verdict(CONDITIONAL, inbound.String()+" keeps applying to the dependency, which must be distributed with its source and kept in separate files or linked dynamically")
+		}
+		return gplCompatibility(in, out, verdict)
+	default:
+		// Strong and network copyleft licenses require the whole work to be distributed under their terms
+		if !out.category.IsCopyleft() || out.category == classification.CATEGORY_WEAK_COPYLEFT {
+			return verdict(INCOMPATIBLE, inbound.String()+" requires the whole work to be distributed under its terms, which "+outbound.String()+" does not")
+		}
+		if in.gnu && out.gnu {
+			return gplCompatibility(in, out, verdict)
+		}
+		return verdict(INCOMPATIBLE, inbound.String()+" and "+outbound.String()+" both require the whole work to be distributed under their own terms")
+	}
+}
+
+// license is a license of an expression, broken down into the parts compatibility depends on.
+type license struct {
+	category classification.Category
+	// family is the name of the license without its version, e.g. "GPL" for "GPL-2.0-or-later"
+	family  string
+	version float64
+	orLater bool
+	// gnu is set on the GPL, LGPL and AGPL licenses, whose compatibility depends on their versions, with or without an exception
+	gnu bool
+}
+
+func parseLicense(leaf spdx.Expression) license {
+	parsed := license{category: classification.ClassifyLicense(leaf)}
+	parsed.orLater = leaf.OrLater || strings.HasSuffix(leaf.License, "-or-later") || strings.HasSuffix(leaf.License, "+")
+
+	family := classification.Family(leaf.License)
+	index := strings.LastIndex(family, "-")
+	if index < 0 {
+		parsed.family = family
+		return parsed
+	}
+	parsed.family = family[:index]
+	parsed.version, _ = strconv.ParseFloat(family[index+1:], 64)
+	parsed.gnu = (parsed.family == "GPL" || parsed.family == "LGPL" || parsed.family == "AGPL") && parsed.version > 0
+	return parsed
+}
+
+// gplCompatibility checks the compatibility of a copyleft dependency with a GPL, LGPL or AGPL project.
+// A GNU license is compatible with another GNU license when they share a version, the "or later" option allowing to upgrade the version of the dependency.
+// The LGPL allows relicensing under the GPL of the same (LGPL-3.0) or of any later version (LGPL-2.1), and the AGPL-3.0 and GPL-3.0 can be combined.
+// The MPL-2.0 is compatible with the GPL through its secondary license clause, the other weak copyleft licenses are not.
+func gplCompatibility(in license, out license, verdict func(Compatibility, string) Verdict) Verdict {
+	if !in.gnu {
+		if in.family == "MPL" && in.version >= 2 {
+			return verdict(COMPATIBLE, "the MPL-2.0 allows the dependency to be distributed under the GNU licenses")
+		}
+		return verdict(INCOMPATIBLE, "the copyleft of the dependency is not compatible with the GNU licenses")
+	}
+	if !out.gnu {
+		return verdict(INCOMPATIBLE, "the GNU license of the dependency is not compatible with the copyleft of the project")
+	}
+
+	inVersion, inOrLater := in.version, in.orLater
+	switch {
+	case in.family == "LGPL" && out.family != "LGPL" && in.version < 3:
+		// The LGPL-2.x can be converted to the GPL-2.0 or any later version
+		inVersion, inOrLater = 2, true
+	case in.family == "LGPL" && out.family != "LGPL":
+		inVersion = 3
+	}
+
+	if in.family == "AGPL" && out.family != "AGPL" && out.version < 3 && !out.orLater {
+		return verdict(INCOMPATIBLE, "the AGPL-3.0 can only be combined with version 3 of the GPL")
+	}
+	if out.family == "LGPL" && in.family != "LGPL" {
+		return verdict(INCOMPATIBLE, "the GPL and AGPL require the whole work to be distributed under their terms, which the LGPL does not")
+	}
+
+	switch {
+	case inVersion == out.version:
+		return verdict(COMPATIBLE, "the dependency and the project share the same version of the GNU licenses")
+	case inVersion < out.version && inOrLater:
+		return verdict(COMPATIBLE, "the dependency can be distributed under a later version of its license")
+	case inVersion > out.version && out.orLater:
+		return verdict(CONDITIONAL, "the project must be distributed under version "+strconv.FormatFloat(inVersion, 'f', 1, 64)+" of its license to include the dependency")
+	default:
+		return verdict(INCOMPATIBLE, "the versions of the GNU licenses of the dependency and of the project cannot be combined")
+	}
+}
+
+// best returns the most compatible of a set of verdicts, the first one in case of a tie.
+func best(verdicts []Verdict) Verdict {
+	result := verdicts[0]
+	for _, verdict := range verdicts[1:] {
+		if rank[verdict.Compatibility] > rank[result.Compatibility] {
+			result = verdict
+		}
+	}
+	return result
+}
+
+// worst returns the least compatible of a set of verdicts, the first one in case of a tie.
+func worst(verdicts []Verdict) Verdict {
+	result := verdicts[0]
+	for _, verdict := range verdicts[1:] {
+		if rank[verdict.Compatibility] < rank[result.Compatibility] {
+			result = verdict
+		}
+	}
+	return result
+}
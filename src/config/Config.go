@@ -0,0 +1,321 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/classification"
+	"github.com/CodeClarityCE/plugin-sca-license/src/policy"
+	"github.com/CodeClarityCE/plugin-sca-license/src/semver"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+	knowledge "github.com/CodeClarityCE/utility-types/knowledge_db"
+)
+
+// DEFAULT_CONCURRENCY is the number of workspaces and SBOMs processed at once when the analysis does not configure it
+const DEFAULT_CONCURRENCY = 4
+
+// Config holds the options of a license analysis, as set in the analysis configuration of the plugin.
+type Config struct {
+	// LicensePolicy is the legacy list of disallowed licenses, denied on top of the rules of Policy
+	LicensePolicy knowledge.LicensePolicy
+	// Policy holds the rules dependency licenses are evaluated against
+	Policy policy.Policy
+	// DependencyScope tells whether dev dependencies are evaluated, against Policy or against DevPolicy
+	DependencyScope policy.ScopeMode
+	// DevPolicy holds the rules dev dependencies are evaluated against, when DependencyScope is policy.SCOPE_MODE_SEPARATE.
+	// It is nil when the analysis does not configure any, dev dependencies are then evaluated against the policy of the analysis.
+	DevPolicy *policy.Policy
+	// ProjectLicense is the license of the project dependency licenses are checked for compatibility with,
+	// empty to skip the check, as the SBOM does not declare the license of the project
+	ProjectLicense spdx.Expression
+	// Concurrency is the maximum number of workspaces processed at once, across the SBOMs processed at once
+	Concurrency int
+}
+
+// Default returns the configuration used when an analysis does not set any option.
+func Default() Config {
+	return Config{
+		LicensePolicy:   knowledge.LicensePolicy{},
+		Policy:          policy.Default(),
+		DependencyScope: policy.SCOPE_MODE_ALL,
+		Concurrency:     DEFAULT_CONCURRENCY,
+	}
+}
+
+// EffectivePolicy returns the policy of the analysis, that is the rules of Policy along with the disallowed licenses of LicensePolicy.
+func (c Config) EffectivePolicy() policy.Policy {
+	effective := c.Policy
+	effective.DeniedLicenses = append(append([]string{}, c.Policy.DeniedLicenses...), c.LicensePolicy.DisallowedLicense...)
+	return effective
+}
+
+// ScopedPolicy returns the policies of the runtime and of the dev dependencies of the analysis.
+// Without DevPolicy, dev dependencies are evaluated against the policy of the analysis, the legacy disallowed licenses included.
+// Waivers apply to dev dependencies as well, whichever policy they are evaluated against.
+func (c Config) ScopedPolicy() policy.ScopedPolicy {
+	runtime := c.EffectivePolicy()
+	if c.DevPolicy == nil {
+		return policy.ScopedPolicy{Mode: c.DependencyScope, Runtime: runtime, Dev: runtime}
+	}
+	dev := *c.DevPolicy
+	dev.Waivers = c.Policy.Waivers
+	return policy.ScopedPolicy{Mode: c.DependencyScope, Runtime: runtime, Dev: dev}
+}
+
+// Parse reads the configuration of an analysis from the plugin section of the analysis document.
+// Options that are not set keep their default value.
+// An error is returned if an option does not have the expected type.
+func Parse(messageData map[string]any) (Config, error) {
+	config := Default()
+
+	if messageData["licensePolicy"] != nil {
+		licenses, err := stringList(messageData["licensePolicy"])
+		if err != nil {
+			return config, fmt.Errorf("invalid licensePolicy: %w", err)
+		}
+		config.LicensePolicy.DisallowedLicense = licenses
+	}
+
+	if messageData["licensePolicyRules"] != nil {
+		rules, err := policyRules(messageData["licensePolicyRules"])
+		if err != nil {
+			return config, fmt.Errorf("invalid licensePolicyRules: %w", err)
+		}
+		config.Policy = rules
+	}
+
+	if messageData["devLicensePolicyRules"] != nil {
+		rules, err := policyRules(messageData["devLicensePolicyRules"])
+		if err != nil {
+			return config, fmt.Errorf("invalid devLicensePolicyRules: %w", err)
+		}
+		config.DevPolicy = &rules
+	}
+
+	if messageData["dependencyScope"] != nil {
+		name, _ := messageData["dependencyScope"].(string)
+		scope, ok := policy.ParseScopeMode(name)
+		if !ok {
+			return config, fmt.Errorf("invalid dependencyScope %v, expected %q, %q or %q", messageData["dependencyScope"], policy.SCOPE_MODE_RUNTIME, policy.SCOPE_MODE_ALL, policy.SCOPE_MODE_SEPARATE)
+		}
+		config.DependencyScope = scope
+	}
+
+	// Waivers are parsed after the rules, which would otherwise replace them
+	if messageData["licenseWaivers"] != nil {
+		waivers, err := waivers(messageData["licenseWaivers"])
+		if err != nil {
+			return config, fmt.Errorf("invalid licenseWaivers: %w", err)
+		}
+		config.Policy.Waivers = waivers
+	}
+
+	if messageData["projectLicense"] != nil {
+		expression, _ := messageData["projectLicense"].(string)
+		parsed, err := spdx.Parse(expression)
+		if err != nil {
+			return config, fmt.Errorf("invalid projectLicense: %w", err)
+		}
+		config.ProjectLicense, _ = parsed.ReplaceDeprecated()
+	}
+
+	if messageData["concurrency"] != nil {
+		concurrency, err := positiveInt(messageData["concurrency"])
+		if err != nil {
+			return config, fmt.Errorf("invalid concurrency: %w", err)
+		}
+		config.Concurrency = concurrency
+	}
+
+	return config, nil
+}
+
+// policyRules reads the rules of a license policy from a JSON object such as
+// {"mode": "allowlist", "allowedLicenses": ["MIT", "GPL-2.0-or-later"], "allowedExceptions": ["Classpath-exception-2.0"],
+// "deniedCategories": ["strong_copyleft"], "unknownLicenses": "deny",
+// "defaultSeverity": "block", "severities": {"weak_copyleft": "warn", "unknown_license": "info"}}.
+func policyRules(value any) (policy.Policy, error) {
+	rules := policy.Default()
+
+	object, ok := value.(map[string]any)
+	if !ok {
+		return rules, fmt.Errorf("expected an object, got %T", value)
+	}
+
+	if object["mode"] != nil {
+		name, _ := object["mode"].(string)
+		mode, ok := policy.ParseMode(name)
+		if !ok {
+			return rules, fmt.Errorf("invalid mode %v, expected %q or %q", object["mode"], policy.MODE_DENYLIST, policy.MODE_ALLOWLIST)
+		}
+		rules.Mode = mode
+	}
+
+	if object["unknownLicenses"] != nil {
+		name, _ := object["unknownLicenses"].(string)
+		handling, ok := policy.ParseUnknownHandling(name)
+		if !ok {
+			return rules, fmt.Errorf("invalid unknownLicenses %v, expected %q, %q or %q", object["unknownLicenses"], policy.UNKNOWN_REVIEW, policy.UNKNOWN_ALLOW, policy.UNKNOWN_DENY)
+		}
+		rules.UnknownLicenses = handling
+	}
+
+	if object["defaultSeverity"] != nil {
+		name, _ := object["defaultSeverity"].(string)
+		severity, ok := policy.ParseSeverity(name)
+		if !ok {
+			return rules, fmt.Errorf("invalid defaultSeverity %v, expected one of %v", object["defaultSeverity"], policy.Severities)
+		}
+		rules.DefaultSeverity = severity
+	}
+
+	if object["severities"] != nil {
+		severities, ok := object["severities"].(map[string]any)
+		if !ok {
+			return rules, fmt.Errorf("invalid severities: expected an object, got %T", object["severities"])
+		}
+		for key, value := range severities {
+			name, _ := value.(string)
+			severity, ok := policy.ParseSeverity(name)
+			if !ok {
+				return rules, fmt.Errorf("invalid severity %v of %q, expected one of %v", value, key, policy.Severities)
+			}
+			rules.Severities[key] = severity
+		}
+	}
+
+	for option, licenses := range map[string]*[]string{
+		"allowedLicenses":   &rules.AllowedLicenses,
+		"deniedLicenses":    &rules.DeniedLicenses,
+		"allowedExceptions": &rules.AllowedExceptions,
+	} {
+		if object[option] == nil {
+			continue
+		}
+		parsed, err := stringList(object[option])
+		if err != nil {
+			return rules, fmt.Errorf("invalid %s: %w", option, err)
+		}
+		*licenses = parsed
+	}
+
+	for option, categories := range map[string]*[]classification.Category{
+		"allowedCategories": &rules.AllowedCategories,
+		"deniedCategories":  &rules.DeniedCategories,
+	} {
+		if object[option] == nil {
+			continue
+		}
+		names, err := stringList(object[option])
+		if err != nil {
+			return rules, fmt.Errorf("invalid %s: %w", option, err)
+		}
+		for _, name := range names {
+			category, ok := classification.ParseCategory(name)
+			if !ok {
+				return rules, fmt.Errorf("invalid %s: unknown category %q", option, name)
+			}
+			*categories = append(*categories, category)
+		}
+	}
+
+	return rules, nil
+}
+
+// waivers reads the waivers of a license policy from a JSON array of objects such as
+// {"package": "gulp", "versions": "^4.0.0", "workspace": ".", "license": "GPL-3.0-only", "justification": "Build tool, never shipped", "expires": "2026-12-31"}.
+// The package, justification and expiry are required. An expiry date is inclusive, the waiver applies until the end of that day (UTC).
+func waivers(value any) ([]policy.Waiver, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an array of objects, got %T", value)
+	}
+
+	result := []policy.Waiver{}
+	for index, item := range items {
+		object, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("waiver %d: expected an object, got %T", index, item)
+		}
+
+		waiver := policy.Waiver{}
+		for option, field := range map[string]*string{
+			"package":       &waiver.Package,
+			"versions":      &waiver.VersionRange,
+			"workspace":     &waiver.Workspace,
+			"license":       &waiver.License,
+			"justification": &waiver.Justification,
+		} {
+			if object[option] == nil {
+				continue
+			}
+			str, ok := object[option].(string)
+			if !ok {
+				return nil, fmt.Errorf("waiver %d: expected %s to be a string, got %T", index, option, object[option])
+			}
+			*field = str
+		}
+		if waiver.Package == "" || waiver.Justification == "" {
+			return nil, fmt.Errorf("waiver %d: package and justification are required", index)
+		}
+
+		if waiver.VersionRange != "" {
+			versions, err := semver.ParseRange(waiver.VersionRange)
+			if err != nil {
+				return nil, fmt.Errorf("waiver %d: %w", index, err)
+			}
+			waiver.Versions = &versions
+		}
+
+		expires, _ := object["expires"].(string)
+		if date, err := time.Parse(time.DateOnly, expires); err == nil {
+			waiver.Expires = date.AddDate(0, 0, 1)
+		} else if instant, err := time.Parse(time.RFC3339, expires); err == nil {
+			waiver.Expires = instant
+		} else {
+			return nil, fmt.Errorf("waiver %d: expected expires to be a date (YYYY-MM-DD) or an RFC 3339 timestamp, got %v", index, object["expires"])
+		}
+
+		result = append(result, waiver)
+	}
+	return result, nil
+}
+
+// stringList reads a JSON array of strings.
+func stringList(value any) ([]string, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an array of strings, got %T", value)
+	}
+
+	result := []string{}
+	for _, item := range items {
+		str, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected an array of strings, got an element of type %T", item)
+		}
+		result = append(result, str)
+	}
+	return result, nil
+}
+
+// positiveInt reads a JSON number that must be a strictly positive integer.
+func positiveInt(value any) (int, error) {
+	var number int
+	switch typed := value.(type) {
+	case float64:
+		if typed != float64(int(typed)) {
+			return 0, fmt.Errorf("expected an integer, got %v", typed)
+		}
+		number = int(typed)
+	case int:
+		number = typed
+	default:
+		return 0, fmt.Errorf("expected a number, got %T", value)
+	}
+
+	if number < 1 {
+		return 0, fmt.Errorf("expected a positive number, got %d", number)
+	}
+	return number, nil
+}
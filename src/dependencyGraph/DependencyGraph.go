@@ -0,0 +1,159 @@
+package dependencyGraph
+
+import (
+	"cmp"
+	"slices"
+
+	sbomTypes "github.com/CodeClarityCE/plugin-sbom-javascript/src/types/sbom/js"
+	"github.com/CodeClarityCE/plugin-sca-license/src/semver"
+)
+
+// Node is a version of a package of the dependency graph of a workspace.
+type Node struct {
+	Name    string
+	Version string
+}
+
+// Graph is the dependency graph of a workspace: its direct dependencies, and the dependencies required by every version.
+type Graph struct {
+	direct []Node
+	edges  map[Node][]Node
+	// distances and parents record, for every node reachable from a direct dependency,
+	// its distance to the closest direct dependency and its parents on the shortest paths
+	distances map[Node]int
+	parents   map[Node][]Node
+}
+
+// New builds the dependency graph of a workspace of an SBOM.
+// Requirements are resolved to the highest version of the workspace that satisfies their constraint.
+// Requirements that cannot be resolved, such as git or aliased dependencies with several versions, are left out.
+func New(workspace sbomTypes.WorkSpace) *Graph {
+	graph := &Graph{edges: map[Node][]Node{}}
+
+	starts := append(append([]sbomTypes.WorkSpaceDependency{}, workspace.Start.Dependencies...), workspace.Start.DevDependencies...)
+	for _, start := range starts {
+		constraint := start.Constraint
+		if start.Version != "" {
+			constraint = start.Version
+		}
+		if node, found := resolve(workspace.Dependencies, start.Name, constraint); found && !slices.Contains(graph.direct, node) {
+			graph.direct = append(graph.direct, node)
+		}
+	}
+	slices.SortFunc(graph.direct, compareNodes)
+
+	for name, versions := range workspace.Dependencies {
+		for version, dependency := range versions {
+			node := Node{Name: name, Version: version}
+			for requiredName, constraint := range dependency.Requires {
+				if required, found := resolve(workspace.Dependencies, requiredName, constraint); found {
+					graph.edges[node] = append(graph.edges[node], required)
+				}
+			}
+			slices.SortFunc(graph.edges[node], compareNodes)
+		}
+	}
+
+	graph.search()
+	return graph
+}
+
+// search runs a breadth first search from every direct dependency at once, recording the shortest paths to every node.
+func (g *Graph) search() {
+	g.distances = map[Node]int{}
+	g.parents = map[Node][]Node{}
+
+	queue := []Node{}
+	for _, node := range g.direct {
+		g.distances[node] = 0
+		queue = append(queue, node)
+	}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		for _, next := range g.edges[node] {
+			distance, visited := g.distances[next]
+			switch {
+			case !visited:
+				g.distances[next] = g.distances[node] + 1
+				g.parents[next] = []Node{node}
+				queue = append(queue, next)
+			case distance == g.distances[node]+1:
+				g.parents[next] = append(g.parents[next], node)
+			}
+		}
+	}
+}
+
+// IsDirect reports whether a dependency is a direct dependency of the workspace.
+func (g *Graph) IsDirect(node Node) bool {
+	return slices.Contains(g.direct, node)
+}
+
+// ShortestPaths returns up to limit shortest paths from a direct dependency of the workspace to a dependency.
+// Every path starts with a direct dependency and ends with the dependency, the only path of a direct dependency is itself.
+// Paths are returned in a deterministic order. No path is returned if the dependency cannot be reached.
+func (g *Graph) ShortestPaths(target Node, limit int) [][]Node {
+	if _, reachable := g.distances[target]; !reachable {
+		return [][]Node{}
+	}
+
+	paths := [][]Node{}
+	var walk func(node Node, suffix []Node)
+	walk = func(node Node, suffix []Node) {
+		if len(paths) >= limit {
+			return
+		}
+		path := append([]Node{node}, suffix...)
+		if g.distances[node] == 0 {
+			paths = append(paths, path)
+			return
+		}
+		for _, parent := range g.parents[node] {
+			walk(parent, path)
+		}
+	}
+	walk(target, []Node{})
+
+	return paths
+}
+
+// resolve finds the version of a package of the workspace that a requirement resolves to.
+// A constraint that is a version of the workspace resolves to it, otherwise the highest version satisfying the constraint is used.
+// A constraint that is not a version range resolves to the only version of the package, if there is only one.
+func resolve(dependencies map[string]map[string]sbomTypes.Versions, name string, constraint string) (Node, bool) {
+	versions := dependencies[name]
+	if _, found := versions[constraint]; found {
+		return Node{Name: name, Version: constraint}, true
+	}
+
+	versionRange, err := semver.ParseRange(constraint)
+	if err != nil {
+		if len(versions) == 1 {
+			for version := range versions {
+				return Node{Name: name, Version: version}, true
+			}
+		}
+		return Node{}, false
+	}
+
+	var best *semver.Version
+	bestName := ""
+	for version := range versions {
+		parsed, err := semver.Parse(version)
+		if err != nil || !versionRange.Contains(parsed) {
+			continue
+		}
+		if best == nil || parsed.Compare(*best) > 0 {
+			best, bestName = &parsed, version
+		}
+	}
+	if best == nil {
+		return Node{}, false
+	}
+	return Node{Name: name, Version: bestName}, true
+}
+
+func compareNodes(a Node, b Node) int {
+	return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Version, b.Version))
+}
@@ -0,0 +1,54 @@
+package ecosystem
+
+import "github.com/CodeClarityCE/plugin-sca-license/src/purl"
+
+// Ecosystem is the package registry dependencies are published to.
+// Two ecosystems may have packages with the same name, which are unrelated.
+type Ecosystem string
+
+const (
+	ECOSYSTEM_NPM       Ecosystem = "npm"
+	ECOSYSTEM_PACKAGIST Ecosystem = "packagist"
+)
+
+// languageEcosystems maps the language of an SBOM, as received by Start, onto its ecosystem
+var languageEcosystems = map[string]Ecosystem{
+	"JS":  ECOSYSTEM_NPM,
+	"PHP": ECOSYSTEM_PACKAGIST,
+}
+
+// knowledgeLanguages maps every ecosystem onto the language its packages are stored under in the knowledge base
+var knowledgeLanguages = map[Ecosystem]string{
+	ECOSYSTEM_NPM:       "javascript",
+	ECOSYSTEM_PACKAGIST: "php",
+}
+
+// purlTypes maps every ecosystem onto the type of the package URLs of its packages
+var purlTypes = map[Ecosystem]string{
+	ECOSYSTEM_NPM:       "npm",
+	ECOSYSTEM_PACKAGIST: "composer",
+}
+
+// FromLanguage returns the ecosystem of an SBOM language (e.g. "JS").
+// It returns false if the language is not supported.
+func FromLanguage(languageId string) (Ecosystem, bool) {
+	ecosystem, ok := languageEcosystems[languageId]
+	return ecosystem, ok
+}
+
+// KnowledgeLanguage returns the value of the language column of the packages of the ecosystem in the knowledge base.
+func (e Ecosystem) KnowledgeLanguage() string {
+	return knowledgeLanguages[e]
+}
+
+// PurlType returns the type of the package URLs of the ecosystem (e.g. "composer" for packagist).
+func (e Ecosystem) PurlType() string {
+	return purlTypes[e]
+}
+
+// DependencyKey identifies a dependency of the ecosystem in the output.
+// It is the canonical package URL of the dependency, e.g. "pkg:npm/%40babel/core@7.0.0" or "pkg:composer/symfony/console@6.4.0".
+// Package URLs normalize the case of some names, so the key must not be used to tell dependencies apart during the analysis.
+func (e Ecosystem) DependencyKey(name string, version string) string {
+	return purl.New(e.PurlType(), name, version).String()
+}
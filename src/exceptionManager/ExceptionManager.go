@@ -0,0 +1,46 @@
+package exceptionManager
+
+import (
+	"sync"
+
+	"github.com/CodeClarityCE/utility-types/exceptions"
+)
+
+// Errors collects the errors of a single analysis, so that concurrent analyses, and the analyses that follow them
+// in the same process, do not report each other's errors.
+// Errors are built directly rather than through the global error collection of the exceptions package,
+// which is shared by every analysis of the process and never emptied.
+// It is safe for concurrent use.
+type Errors struct {
+	mutex  sync.Mutex
+	errors []exceptions.Error
+}
+
+// NewErrors creates an empty error collection.
+func NewErrors() *Errors {
+	return &Errors{errors: []exceptions.Error{}}
+}
+
+// AddError records an error of the analysis, with the same parameters as exceptions.AddError.
+func (e *Errors) AddError(publicDescription string, publicType exceptions.ERROR_TYPE, privateDescription string, privateType exceptions.ERROR_TYPE) {
+	e.Append(exceptions.Error{
+		Public:  exceptions.PublicError{Description: publicDescription, Type: publicType},
+		Private: exceptions.PrivateError{Description: privateDescription, Type: privateType},
+	})
+}
+
+// Append records errors collected elsewhere, such as the errors of the analysis of a single SBOM.
+func (e *Errors) Append(errors ...exceptions.Error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	e.errors = append(e.errors, errors...)
+}
+
+// GetErrors returns a copy of the errors recorded so far, in the order they were recorded in.
+func (e *Errors) GetErrors() []exceptions.Error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return append([]exceptions.Error{}, e.errors...)
+}
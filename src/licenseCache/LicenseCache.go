@@ -0,0 +1,215 @@
+package licenseCache
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/ecosystem"
+	licenseRepository "github.com/CodeClarityCE/plugin-sca-license/src/repository/license"
+	"github.com/uptrace/bun"
+)
+
+const (
+	// DEFAULT_CAPACITY is the number of dependencies kept in the cache when the plugin does not configure it
+	DEFAULT_CAPACITY = 50000
+	// DEFAULT_TTL is how long a dependency stays in the cache when the plugin does not configure it
+	DEFAULT_TTL = 24 * time.Hour
+)
+
+// Key identifies a dependency in the cache.
+type Key struct {
+	Ecosystem ecosystem.Ecosystem
+	Name      string
+	Version   string
+}
+
+// Stats counts the lookups answered by the cache.
+type Stats struct {
+	Hits   int64
+	Misses int64
+}
+
+// Recorder accumulates the statistics of the lookups of a single analysis.
+// It is safe for concurrent use.
+type Recorder struct {
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// Stats returns the statistics recorded so far.
+func (r *Recorder) Stats() Stats {
+	return Stats{Hits: r.hits.Load(), Misses: r.misses.Load()}
+}
+
+type entry struct {
+	key       Key
+	value     licenseRepository.DeclaredLicense
+	expiresAt time.Time
+}
+
+// LicenseCache is a least recently used cache of the licenses declared by dependencies, bounded in size and in age.
+// It lives as long as the plugin process, so that dependencies found in nearly every project are only looked up once.
+// It is safe for concurrent use.
+type LicenseCache struct {
+	mutex    sync.Mutex
+	capacity int
+	ttl      time.Duration
+	// entries maps every key onto its element in order, the most recently used element being at the front
+	entries map[Key]*list.Element
+	order   *list.List
+	// fingerprint identifies the state of the knowledge base the cached entries were read from
+	fingerprint string
+	stats       Stats
+}
+
+// NewLicenseCache creates a cache holding at most capacity dependencies, each for at most ttl.
+func NewLicenseCache(capacity int, ttl time.Duration) *LicenseCache {
+	return &LicenseCache{
+		capacity: max(capacity, 1),
+		ttl:      ttl,
+		entries:  map[Key]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// Get returns the cached license of a dependency.
+// It returns false if the dependency is not cached or its entry has expired.
+func (c *LicenseCache) Get(key Key) (licenseRepository.DeclaredLicense, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, found := c.entries[key]
+	if !found {
+		c.stats.Misses++
+		return licenseRepository.DeclaredLicense{}, false
+	}
+
+	cached := element.Value.(*entry)
+	if time.Now().After(cached.expiresAt) {
+		c.remove(element)
+		c.stats.Misses++
+		return licenseRepository.DeclaredLicense{}, false
+	}
+
+	c.order.MoveToFront(element)
+	c.stats.Hits++
+	return cached.value, true
+}
+
+// Set caches the license of a dependency, evicting the least recently used dependency if the cache is full.
+func (c *LicenseCache) Set(key Key, value licenseRepository.DeclaredLicense) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	expiresAt := time.Now().Add(c.ttl)
+	if element, found := c.entries[key]; found {
+		element.Value = &entry{key: key, value: value, expiresAt: expiresAt}
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// Refresh empties the cache if the knowledge base changed since the cached entries were read,
+// that is if its fingerprint differs from the one of the previous refresh.
+// It reports whether the cache was emptied.
+func (c *LicenseCache) Refresh(fingerprint string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if fingerprint == c.fingerprint {
+		return false
+	}
+	c.fingerprint = fingerprint
+	c.purge()
+	return true
+}
+
+// Purge empties the cache.
+func (c *LicenseCache) Purge() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	// The knowledge base state is unknown, the next refresh must not trust the previous fingerprint
+	c.fingerprint = ""
+	c.purge()
+}
+
+// Len returns the number of cached dependencies, including expired ones that were not evicted yet.
+func (c *LicenseCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.order.Len()
+}
+
+// Stats returns the statistics of every lookup since the cache was created.
+func (c *LicenseCache) Stats() Stats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.stats
+}
+
+// GetDeclaredLicenses retrieves the licenses declared by a set of dependencies of an ecosystem, see licenseRepository.GetDeclaredLicenses.
+// Only the dependencies missing from the cache are looked up in the knowledge base, and their licenses are then cached.
+// Failed queries are not cached. The hits and misses are also counted in recorder, if any.
+// A nil cache looks every dependency up in the knowledge base.
+func (c *LicenseCache) GetDeclaredLicenses(knowledge_db *bun.DB, dependencyEcosystem ecosystem.Ecosystem, dependencies []licenseRepository.Dependency, recorder *Recorder) (map[licenseRepository.Dependency]licenseRepository.DeclaredLicense, error) {
+	if c == nil {
+		return licenseRepository.GetDeclaredLicenses(knowledge_db, dependencyEcosystem, dependencies)
+	}
+
+	declaredLicenses := map[licenseRepository.Dependency]licenseRepository.DeclaredLicense{}
+	missing := []licenseRepository.Dependency{}
+	for _, dependency := range dependencies {
+		if declared, found := c.Get(cacheKey(dependencyEcosystem, dependency)); found {
+			declaredLicenses[dependency] = declared
+			if recorder != nil {
+				recorder.hits.Add(1)
+			}
+			continue
+		}
+		missing = append(missing, dependency)
+		if recorder != nil {
+			recorder.misses.Add(1)
+		}
+	}
+
+	if len(missing) == 0 {
+		return declaredLicenses, nil
+	}
+
+	fetched, err := licenseRepository.GetDeclaredLicenses(knowledge_db, dependencyEcosystem, missing)
+	if err != nil {
+		return nil, err
+	}
+	for dependency, declared := range fetched {
+		c.Set(cacheKey(dependencyEcosystem, dependency), declared)
+		declaredLicenses[dependency] = declared
+	}
+
+	return declaredLicenses, nil
+}
+
+func cacheKey(dependencyEcosystem ecosystem.Ecosystem, dependency licenseRepository.Dependency) Key {
+	return Key{Ecosystem: dependencyEcosystem, Name: dependency.Name, Version: dependency.Version}
+}
+
+// remove evicts an element, the caller must hold the mutex.
+func (c *LicenseCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*entry).key)
+}
+
+// purge evicts every element, the caller must hold the mutex.
+func (c *LicenseCache) purge() {
+	c.entries = map[Key]*list.Element{}
+	c.order.Init()
+}
@@ -1,12 +1,26 @@
 package matcher
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"slices"
 
 	sbomTypes "github.com/CodeClarityCE/plugin-sbom-javascript/src/types/sbom/js"
+	"github.com/CodeClarityCE/plugin-sca-license/src/classification"
+	"github.com/CodeClarityCE/plugin-sca-license/src/compatibility"
+	"github.com/CodeClarityCE/plugin-sca-license/src/dependencyGraph"
+	"github.com/CodeClarityCE/plugin-sca-license/src/ecosystem"
+	"github.com/CodeClarityCE/plugin-sca-license/src/licenseCache"
+	"github.com/CodeClarityCE/plugin-sca-license/src/licenseMatcher/interpreter"
+	"github.com/CodeClarityCE/plugin-sca-license/src/licenseMatcher/normalizer"
+	"github.com/CodeClarityCE/plugin-sca-license/src/licenseMatcher/textMatcher"
+	"github.com/CodeClarityCE/plugin-sca-license/src/policy"
 	licenseRepository "github.com/CodeClarityCE/plugin-sca-license/src/repository/license"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
 	types "github.com/CodeClarityCE/plugin-sca-license/src/types"
 	knowledge "github.com/CodeClarityCE/utility-types/knowledge_db"
 	"github.com/uptrace/bun"
@@ -19,42 +33,247 @@ const (
 	LICENSE_DATA_SOURCE_DB   LicenseDataSource = "LICENSE_DATA_SOURCE_DB"
 )
 
+// excerptLength is the maximum length of the license text excerpts kept in the output
+const excerptLength = 80
+
+// maxIntroducedByPaths is the maximum number of paths reported for a finding on a transitive dependency
+const maxIntroducedByPaths = 3
+
 type LicenseMatcher struct {
 	PostProcessLicenses bool
 	LicenseDataSource   LicenseDataSource
+	// Ecosystem is the package registry of the analyzed SBOM, packages are only looked up in this ecosystem
+	Ecosystem ecosystem.Ecosystem
+	// Cache holds the licenses declared by dependencies across analyses, nil disables caching
+	Cache *licenseCache.LicenseCache
+	// CacheRecorder counts the cache hits and misses of the current analysis
+	CacheRecorder *licenseCache.Recorder
+	// Normalizer maps the identifiers that are not SPDX licenses onto SPDX licenses, when PostProcessLicenses is set
+	Normalizer *normalizer.Normalizer
+	// TextMatcher identifies the SPDX license of declared licenses that are license texts, when PostProcessLicenses is set
+	TextMatcher *textMatcher.TextMatcher
+	// EvaluatedAt is the instant the expiry of waivers is checked against
+	EvaluatedAt time.Time
+	// VersionSeperator and ImportPathSeperator format the dependency paths of findings, e.g. "express@4.18.2 -> qs@6.11.0"
+	VersionSeperator    string
+	ImportPathSeperator string
+	// ProjectLicense is the configured license of the project, the compatibility of dependency licenses is not checked when it is empty
+	ProjectLicense spdx.Expression
+}
+
+// LoadSPDXLicenses prepares the post processing of licenses against the given SPDX licenses.
+// Deprecated licenses are only used to normalize identifiers, texts are matched against current licenses.
+func (lm *LicenseMatcher) LoadSPDXLicenses(licenses []knowledge.License) {
+	licenseIds := []string{}
+	candidates := []textMatcher.Candidate{}
+	for _, license := range licenses {
+		licenseIds = append(licenseIds, license.LicenseID)
+		if license.Details.IsDeprecatedLicenseId {
+			continue
+		}
+		candidates = append(candidates, textMatcher.Candidate{
+			LicenseID: license.LicenseID,
+			Text:      license.Details.LicenseText,
+			Template:  license.Details.StandardLicenseTemplate,
+		})
+	}
+
+	lm.Normalizer = normalizer.NewNormalizer(licenseIds)
+	lm.TextMatcher = textMatcher.NewTextMatcher(candidates)
 }
 
-func (lm LicenseMatcher) GetWorkSpaceLicenses(knowledge_db *bun.DB, dependencies map[string]map[string]sbomTypes.Versions, licensePolicy knowledge.LicensePolicy) types.WorkSpaceLicenseInfoInternal {
+// GetWorkSpaceLicenses resolves the licenses of the dependencies of a workspace and evaluates them against the license policy.
+// Dev dependencies are evaluated according to the scope mode of the policy, violations approved by a waiver of the workspace are reported as waived.
+// Findings carry whether their dependency is a direct dependency of the workspace, and the shortest paths it was introduced by.
+// When the project license is known, the licenses of the runtime dependencies are checked for compatibility with it.
+func (lm LicenseMatcher) GetWorkSpaceLicenses(knowledge_db *bun.DB, workspaceName string, workspace sbomTypes.WorkSpace, licensePolicy policy.ScopedPolicy) types.WorkSpaceLicenseInfoInternal {
+	dependencies := workspace.Dependencies
+	graph := dependencyGraph.New(workspace)
+
 	licensesDepMap := map[string][]string{}
 	nonSpdxLicensesDepMap := map[string][]string{}
+	proprietaryDependencies := []string{}
 	licenseComplianceViolations := map[string][]string{}
+	policyViolations := []types.PolicyViolation{}
+	licenseIncompatibilities := []types.LicenseIncompatibility{}
+	dependencyInfo := map[string]types.DependencyInfo{}
+	projectLicense, projectLicenseSource := lm.ProjectLicense, types.PROJECT_LICENSE_SOURCE_CONFIG
+	if projectLicense.IsEmpty() {
+		projectLicenseSource = types.PROJECT_LICENSE_SOURCE_NONE
+	}
 
-	for dependency_name, dependency := range dependencies {
-		for version_name := range dependency {
-			key := dependency_name + "@" + version_name
-			if lm.LicenseDataSource == LICENSE_DATA_SOURCE_DB {
-				licenses, err := licenseRepository.GetDependencyLicenses(knowledge_db, dependency_name, version_name)
-				if err != nil {
-					log.Printf("Unable to retrieve linked licenses for package: %s", dependency_name)
-					nonSpdxLicensesDepMap[""] = append(nonSpdxLicensesDepMap[""], key)
-					continue
+	// applyPolicy records the evaluation of the licenses of a dependency against its policy
+	applyPolicy := func(key string, info *types.DependencyInfo, dependencyPolicy policy.Policy, result policy.Result) {
+		info.PolicyVerdict = policyVerdicts[result.Outcome]
+		info.PolicyDecisions = result.Decisions
+		if !result.Elected.IsEmpty() && result.Elected.String() != info.Expression.String() {
+			info.ElectedLicense = result.Elected.String()
+		}
+
+		seen := map[string]bool{}
+		active := 0
+		for _, decision := range result.Denied() {
+			// A license may appear more than once in an expression
+			if seen[decision.License] {
+				continue
+			}
+			seen[decision.License] = true
+
+			violation := types.PolicyViolation{
+				Dependency:   key,
+				License:      decision.License,
+				Expression:   decision.Expression,
+				Rule:         decision.Rule,
+				Severity:     decision.Severity,
+				Status:       types.VIOLATION_STATUS_ACTIVE,
+				Scope:        info.Scope,
+				Direct:       info.Direct,
+				IntroducedBy: lm.introducedBy(graph, info),
+			}
+			if waiver, found := dependencyPolicy.FindWaiver(workspaceName, info.Name, info.Version, decision.License, decision.Expression, lm.EvaluatedAt); found {
+				violation.Waiver = &waiver
+				if !waiver.ExpiredAt(lm.EvaluatedAt) {
+					violation.Status = types.VIOLATION_STATUS_WAIVED
 				}
+			}
+			policyViolations = append(policyViolations, violation)
 
-				// Linked licenses are always spdx, verified during the knowledge base import
-				for _, license := range licenses {
-					deps := append(licensesDepMap[license.LicenseID], key)
-					licensesDepMap[license.LicenseID] = deps
+			if violation.Status == types.VIOLATION_STATUS_ACTIVE {
+				licenseComplianceViolations[decision.License] = append(licenseComplianceViolations[decision.License], key)
+				active++
+			}
+		}
+		if result.Outcome == policy.OUTCOME_DENIED && active == 0 {
+			info.PolicyVerdict = types.POLICY_VERDICT_WAIVED
+		}
+	}
+
+	// checkCompatibility records whether the licenses of a dependency can be distributed under the project license.
+	// Dev dependencies are not shipped with the project, so they are not checked.
+	checkCompatibility := func(key string, info *types.DependencyInfo, expression spdx.Expression, known func(licenseId string) bool) {
+		if projectLicense.IsEmpty() || info.Scope == types.SCOPE_DEV {
+			return
+		}
+		verdict := compatibility.Check(expression, projectLicense, known)
+		info.Compatibility = &verdict
+		if verdict.Compatibility != compatibility.INCOMPATIBLE {
+			return
+		}
+		licenseIncompatibilities = append(licenseIncompatibilities, types.LicenseIncompatibility{
+			Dependency:     key,
+			License:        verdict.Inbound,
+			ProjectLicense: verdict.Outbound,
+			Reason:         verdict.Reason,
+			Severity:       licensePolicy.Runtime.Severity(policy.Rule{Kind: policy.RULE_INCOMPATIBLE_LICENSE, Value: verdict.Inbound}),
+			Direct:         info.Direct,
+			IntroducedBy:   lm.introducedBy(graph, info),
+		})
+	}
+
+	resolutions := lm.resolveDependencies(knowledge_db, dependencies)
+
+	// Dependencies are visited in order, so that the dependency lists of the output are sorted
+	for _, dependency_name := range sortedKeys(dependencies) {
+		for _, version_name := range sortedKeys(dependencies[dependency_name]) {
+			key := lm.Ecosystem.DependencyKey(dependency_name, version_name)
+
+			resolution := resolutions[licenseRepository.Dependency{Name: dependency_name, Version: version_name}]
+			resolved, normalizations, err := resolution.resolved, resolution.normalizations, resolution.err
+
+			version := dependencies[dependency_name][version_name]
+			info := types.DependencyInfo{
+				Name:            dependency_name,
+				Version:         version_name,
+				Licenses:        []string{},
+				NonSpdxLicenses: []string{},
+				DeclaredLicense: resolved.Declared,
+				LicenseFallback: resolved.Fallback,
+				DataSource:      lm.dataSource(),
+				Dev:             version.Dev,
+				Optional:        version.Optional,
+				Bundled:         version.Bundled,
+				Scope:           types.SCOPE_RUNTIME,
+				Direct:          graph.IsDirect(dependencyGraph.Node{Name: dependency_name, Version: version_name}),
+				PolicyVerdict:   types.POLICY_VERDICT_UNKNOWN,
+			}
+			if version.Dev {
+				info.Scope = types.SCOPE_DEV
+			}
+			// Dependencies out of the scope of the policy are reported without being evaluated
+			dependencyPolicy, evaluated := licensePolicy.For(version.Dev)
+			if !evaluated {
+				info.PolicyVerdict = types.POLICY_VERDICT_EXCLUDED
+			}
 
-					if slices.Contains(licensePolicy.DisallowedLicense, license.LicenseID) {
-						deps := append(licenseComplianceViolations[license.LicenseID], key)
-						licenseComplianceViolations[license.LicenseID] = deps
-					}
+			if err != nil {
+				log.Printf("Unable to retrieve linked licenses for package %s: %v", key, err)
+
+				// Unresolved dependencies are kept under the license they declared, if any
+				info.UnresolvedReason = unresolvedReason(err, resolved)
+				// A failed lookup says nothing about the license of the dependency, so it is not reported as non-spdx
+				if licenseRepository.IsKnowledgeBaseError(err) {
+					info.ResolutionStatus = types.RESOLUTION_STATUS_ERROR
+					checkCompatibility(key, &info, spdx.Expression{}, nil)
+					dependencyInfo[key] = info
+					continue
 				}
 
+				info.ResolutionStatus = types.RESOLUTION_STATUS_UNRESOLVED
+				if resolved.Declared != "" {
+					info.NonSpdxLicenses = append(info.NonSpdxLicenses, resolved.Declared)
+				}
+				nonSpdxLicensesDepMap[resolved.Declared] = append(nonSpdxLicensesDepMap[resolved.Declared], key)
+				if evaluated {
+					applyPolicy(key, &info, dependencyPolicy, dependencyPolicy.EvaluateUnknown(resolved.Declared))
+				}
+				checkCompatibility(key, &info, spdx.Expression{}, nil)
+				dependencyInfo[key] = info
+				continue
+			}
+
+			info.NonSpdxLicenses = resolved.Unresolved
+			info.Expression = resolved.Expression
+			info.Normalizations = normalizations
+			switch {
+			case len(resolved.Unresolved) == 0:
+				info.ResolutionStatus = types.RESOLUTION_STATUS_RESOLVED
+			case len(resolved.Licenses) == 0:
+				info.ResolutionStatus = types.RESOLUTION_STATUS_UNRESOLVED
+				info.UnresolvedReason = types.UNRESOLVED_REASON_NON_SPDX_LICENSE
+			default:
+				info.ResolutionStatus = types.RESOLUTION_STATUS_PARTIALLY_RESOLVED
+				info.UnresolvedReason = types.UNRESOLVED_REASON_NON_SPDX_LICENSE
 			}
-			// else if lm.LicenseDataSource == LICENSE_DATA_SOURCE_SBOM {
-			// 	// TODO implement
-			// }
+			info.Confidence = resolutionConfidence(resolved, normalizations)
+
+			// Every license of the expression is matched against the knowledge base
+			for _, license := range resolved.Licenses {
+				deps := append(licensesDepMap[license.LicenseID], key)
+				licensesDepMap[license.LicenseID] = deps
+				info.Licenses = append(info.Licenses, license.LicenseID)
+			}
+
+			// Proprietary dependencies are reported apart from the SPDX licenses
+			if resolved.Proprietary {
+				info.Proprietary = true
+				proprietaryDependencies = append(proprietaryDependencies, key)
+			}
+
+			// Identifiers of the expression that are not SPDX licenses
+			for _, licenseId := range resolved.Unresolved {
+				nonSpdxLicensesDepMap[licenseId] = append(nonSpdxLicensesDepMap[licenseId], key)
+			}
+
+			// Identifiers that are not SPDX licenses are evaluated as unknown licenses
+			known := func(licenseId string) bool {
+				return !slices.Contains(resolved.Unresolved, licenseId)
+			}
+			if evaluated {
+				applyPolicy(key, &info, dependencyPolicy, dependencyPolicy.Evaluate(resolved.Expression, known))
+			}
+			checkCompatibility(key, &info, resolved.Expression, known)
+
+			dependencyInfo[key] = info
 		}
 
 	}
@@ -62,9 +281,364 @@ func (lm LicenseMatcher) GetWorkSpaceLicenses(knowledge_db *bun.DB, dependencies
 	workSpaceLicenseInfo := types.WorkSpaceLicenseInfoInternal{
 		LicensesDepMap:              licensesDepMap,
 		NonSpdxLicensesDepMap:       nonSpdxLicensesDepMap,
+		ProprietaryDependencies:     proprietaryDependencies,
 		LicenseComplianceViolations: licenseComplianceViolations,
+		PolicyViolations:            policyViolations,
+		LicenseIncompatibilities:    licenseIncompatibilities,
+		ProjectLicense:              projectLicense.String(),
+		ProjectLicenseSource:        projectLicenseSource,
+		DependencyInfo:              dependencyInfo,
 	}
 
 	return workSpaceLicenseInfo
 
 }
+
+// dependencyResolution holds the outcome of the license resolution of a single dependency.
+type dependencyResolution struct {
+	resolved licenseRepository.DependencyLicenses
+	// normalizations records how declared licenses that are not SPDX licenses were normalized
+	normalizations []types.LicenseNormalization
+	err            error
+}
+
+// resolveDependencies retrieves the licenses of every dependency of a workspace from the configured license data source.
+// The knowledge base is queried for the whole workspace at once: declared licenses missing from the cache are fetched in a single batch,
+// then the licenses referenced by every expression in another, and the licenses introduced by post processing in a last one.
+// The resolutions are keyed by the exact name and version of the dependencies, the package URLs of the ecosystem only identify
+// dependencies in the output: their normalized names may collide, such as npm names that only differ in case.
+func (lm LicenseMatcher) resolveDependencies(knowledge_db *bun.DB, dependencies map[string]map[string]sbomTypes.Versions) map[licenseRepository.Dependency]*dependencyResolution {
+	resolutions := map[licenseRepository.Dependency]*dependencyResolution{}
+
+	switch lm.LicenseDataSource {
+	case LICENSE_DATA_SOURCE_DB:
+		refs := []licenseRepository.Dependency{}
+		for dependencyName, dependency := range dependencies {
+			for versionName := range dependency {
+				refs = append(refs, licenseRepository.Dependency{Name: dependencyName, Version: versionName})
+			}
+		}
+
+		declaredLicenses, err := lm.Cache.GetDeclaredLicenses(knowledge_db, lm.Ecosystem, refs, lm.CacheRecorder)
+		for _, ref := range refs {
+			resolution := &dependencyResolution{err: err}
+			resolutions[ref] = resolution
+			if err != nil {
+				continue
+			}
+
+			declared := declaredLicenses[ref]
+			resolution.resolved = licenseRepository.DependencyLicenses{Declared: declared.Declared, Fallback: declared.Fallback}
+			resolution.err = declared.Err
+			if resolution.err == nil {
+				resolution.resolved.Expression, resolution.normalizations, resolution.err = lm.ParseLicenses(declared.Entries, declared.LicenseText)
+			}
+		}
+	case LICENSE_DATA_SOURCE_SBOM:
+		for dependencyName, dependency := range dependencies {
+			for versionName, version := range dependency {
+				resolution := &dependencyResolution{}
+				resolution.resolved.Declared = strings.Join(version.Licenses, ", ")
+				resolution.resolved.Expression, resolution.normalizations, resolution.err = lm.ParseSBOMLicenses(version)
+				resolutions[licenseRepository.Dependency{Name: dependencyName, Version: versionName}] = resolution
+			}
+		}
+	default:
+		err := fmt.Errorf("unsupported license data source: %s", lm.LicenseDataSource)
+		for dependencyName, dependency := range dependencies {
+			for versionName := range dependency {
+				resolutions[licenseRepository.Dependency{Name: dependencyName, Version: versionName}] = &dependencyResolution{err: err}
+			}
+		}
+		return resolutions
+	}
+
+	lm.resolveExpressions(knowledge_db, resolutions)
+	if lm.postProcessing() {
+		lm.normalizeUnresolved(knowledge_db, resolutions)
+	}
+
+	return resolutions
+}
+
+// resolveExpressions looks up, in a single batch, the licenses of the expressions of every resolution that did not fail yet.
+// The declared license and the fallback flag of every resolution are kept.
+func (lm LicenseMatcher) resolveExpressions(knowledge_db *bun.DB, resolutions map[licenseRepository.Dependency]*dependencyResolution) {
+	licenseIds := []string{}
+	for _, resolution := range resolutions {
+		if resolution.err == nil {
+			licenseIds = append(licenseIds, resolution.resolved.Expression.Licenses()...)
+		}
+	}
+
+	licenses, err := licenseRepository.GetLicenses(knowledge_db, licenseIds)
+	for _, resolution := range resolutions {
+		if resolution.err != nil {
+			continue
+		}
+		if err != nil {
+			resolution.err = err
+			continue
+		}
+
+		resolved := licenses.Resolve(resolution.resolved.Expression)
+		resolved.Declared = resolution.resolved.Declared
+		resolved.Fallback = resolution.resolved.Fallback
+		resolution.resolved = resolved
+	}
+}
+
+// ParseSBOMLicenses builds the license expression of a dependency from the licenses declared in the SBOM,
+// along with the normalizations of the declared licenses. It does not query the knowledge base.
+// The SBOM does not carry the text of license files.
+func (lm LicenseMatcher) ParseSBOMLicenses(version sbomTypes.Versions) (spdx.Expression, []types.LicenseNormalization, error) {
+	if len(version.Licenses) == 0 {
+		return spdx.Expression{}, nil, licenseRepository.ErrLicenseEmpty
+	}
+	return lm.ParseLicenses(version.Licenses, "")
+}
+
+// ParseLicenses builds the license expression of a dependency from the entries of the license it declares,
+// along with the normalizations of the declared licenses. licenseText is the text of the license file of the dependency, if available.
+// Every entry is parsed on its own, the entries of a license array are then combined with the array operator of the ecosystem
+// (e.g. OR for the legacy npm licenses array and the composer license array). It does not query the knowledge base.
+func (lm LicenseMatcher) ParseLicenses(entries []string, licenseText string) (spdx.Expression, []types.LicenseNormalization, error) {
+	terms := []spdx.Expression{}
+	normalizations := []types.LicenseNormalization{}
+	for _, declared := range entries {
+		parsed, entryNormalizations, err := lm.parseDeclared(declared, licenseText)
+		if err != nil {
+			return spdx.Expression{}, nil, err
+		}
+		terms = append(terms, parsed)
+		normalizations = append(normalizations, entryNormalizations...)
+	}
+
+	return spdx.Join(interpreter.For(lm.Ecosystem).ArrayOperator, terms...), normalizations, nil
+}
+
+// parseDeclared parses a declared license as an SPDX expression, according to the conventions of the ecosystem.
+// A declared license that refers to a license file is identified by matching the text of the file, when it is available.
+// The identifiers that declare a package as proprietary (e.g. "UNLICENSED" for npm, "proprietary" for composer) are replaced by classification.LICENSE_PROPRIETARY
+// and the deprecated licenses (e.g. "GPL-2.0+") by their current equivalents, each replacement being recorded as a normalization.
+func (lm LicenseMatcher) parseDeclared(declared string, licenseText string) (spdx.Expression, []types.LicenseNormalization, error) {
+	ecosystemInterpreter := interpreter.For(lm.Ecosystem)
+	if file, found := ecosystemInterpreter.LicenseFile(declared); found {
+		return lm.matchLicenseFile(declared, file, licenseText)
+	}
+
+	parsed, normalizations, err := lm.parseExpression(declared)
+	if err != nil {
+		return parsed, normalizations, err
+	}
+	parsed = parsed.MapLeaves(func(leaf spdx.Expression) spdx.Expression {
+		if leaf.Exception != "" || !ecosystemInterpreter.IsProprietary(leaf.License) {
+			return leaf
+		}
+		normalizations = append(normalizations, types.LicenseNormalization{
+			Original:   leaf.License,
+			LicenseID:  classification.LICENSE_PROPRIETARY,
+			Confidence: 1,
+			Method:     interpreter.METHOD_PROPRIETARY,
+		})
+		return spdx.NewLicense(classification.LICENSE_PROPRIETARY)
+	})
+	parsed, replacements := parsed.ReplaceDeprecated()
+	return parsed, append(normalizations, toDeprecatedNormalizations(replacements)...), nil
+}
+
+// matchLicenseFile identifies the license of a dependency that refers to a license file (e.g. "SEE LICENSE IN LICENSE.md")
+// by matching the text of the file, when licenses are post processed.
+// An interpreter.ErrLicenseFile error is returned if the text is not available or does not match any SPDX license.
+func (lm LicenseMatcher) matchLicenseFile(declared string, file string, licenseText string) (spdx.Expression, []types.LicenseNormalization, error) {
+	if strings.TrimSpace(licenseText) == "" || !lm.postProcessing() || lm.TextMatcher == nil {
+		return spdx.Expression{}, nil, fmt.Errorf("%w: the text of %s is not available", interpreter.ErrLicenseFile, file)
+	}
+
+	result, ok := lm.TextMatcher.Match(licenseText)
+	if !ok {
+		return spdx.Expression{}, nil, fmt.Errorf("%w: the text of %s does not match any SPDX license", interpreter.ErrLicenseFile, file)
+	}
+	normalization := types.LicenseNormalization{
+		Original:   declared,
+		LicenseID:  result.LicenseID,
+		Confidence: result.Score,
+		Method:     string(result.Method),
+	}
+	return spdx.NewLicense(result.LicenseID), []types.LicenseNormalization{normalization}, nil
+}
+
+// parseExpression parses a declared license as an SPDX expression.
+// When licenses are post processed, a declared license that is not a valid expression is either
+// matched as a license text, when it is one, or normalized as a whole onto a single SPDX license (e.g. "Apache 2").
+func (lm LicenseMatcher) parseExpression(declared string) (spdx.Expression, []types.LicenseNormalization, error) {
+	parsed, err := spdx.Parse(declared)
+	if err == nil || !lm.postProcessing() {
+		return parsed, nil, err
+	}
+
+	if lm.TextMatcher != nil && textMatcher.LooksLikeLicenseText(declared) {
+		result, ok := lm.TextMatcher.Match(declared)
+		if !ok {
+			return spdx.Expression{}, nil, err
+		}
+		normalization := types.LicenseNormalization{
+			Original:   excerpt(declared),
+			LicenseID:  result.LicenseID,
+			Confidence: result.Score,
+			Method:     string(result.Method),
+		}
+		return spdx.NewLicense(result.LicenseID), []types.LicenseNormalization{normalization}, nil
+	}
+
+	result, ok := lm.Normalizer.Normalize(declared)
+	if !ok {
+		return spdx.Expression{}, nil, err
+	}
+	return spdx.NewLicense(result.LicenseID), []types.LicenseNormalization{toLicenseNormalization(result)}, nil
+}
+
+// normalizeUnresolved maps the identifiers of the expressions that are not SPDX licenses (e.g. "BSD")
+// onto SPDX licenses and resolves the rewritten expressions.
+// Identifiers for which no SPDX license matches are kept as they are.
+func (lm LicenseMatcher) normalizeUnresolved(knowledge_db *bun.DB, resolutions map[licenseRepository.Dependency]*dependencyResolution) {
+	rewritten := map[licenseRepository.Dependency]*dependencyResolution{}
+	for key, resolution := range resolutions {
+		if resolution.err != nil || len(resolution.resolved.Unresolved) == 0 {
+			continue
+		}
+
+		replacements := map[string]string{}
+		for _, licenseId := range resolution.resolved.Unresolved {
+			result, ok := lm.Normalizer.Normalize(licenseId)
+			if !ok {
+				continue
+			}
+			replacements[licenseId] = result.LicenseID
+			resolution.normalizations = append(resolution.normalizations, toLicenseNormalization(result))
+		}
+		if len(replacements) == 0 {
+			continue
+		}
+
+		resolution.resolved.Expression = resolution.resolved.Expression.MapLeaves(func(leaf spdx.Expression) spdx.Expression {
+			if replacement, ok := replacements[leaf.License]; ok {
+				leaf.License = replacement
+			}
+			return leaf
+		})
+		var deprecated []spdx.Replacement
+		resolution.resolved.Expression, deprecated = resolution.resolved.Expression.ReplaceDeprecated()
+		resolution.normalizations = append(resolution.normalizations, toDeprecatedNormalizations(deprecated)...)
+		rewritten[key] = resolution
+	}
+
+	if len(rewritten) > 0 {
+		lm.resolveExpressions(knowledge_db, rewritten)
+	}
+}
+
+// introducedBy formats the shortest paths from a direct dependency of the workspace to a dependency,
+// with the version and import path separators of the SBOM.
+func (lm LicenseMatcher) introducedBy(graph *dependencyGraph.Graph, info *types.DependencyInfo) []string {
+	paths := []string{}
+	for _, path := range graph.ShortestPaths(dependencyGraph.Node{Name: info.Name, Version: info.Version}, maxIntroducedByPaths) {
+		nodes := []string{}
+		for _, node := range path {
+			nodes = append(nodes, node.Name+lm.VersionSeperator+node.Version)
+		}
+		paths = append(paths, strings.Join(nodes, lm.ImportPathSeperator))
+	}
+	return paths
+}
+
+// policyVerdicts maps the outcome of the policy evaluation of a dependency onto its verdict
+var policyVerdicts = map[policy.Outcome]types.PolicyVerdict{
+	policy.OUTCOME_ALLOWED: types.POLICY_VERDICT_COMPLIANT,
+	policy.OUTCOME_DENIED:  types.POLICY_VERDICT_VIOLATION,
+	policy.OUTCOME_UNKNOWN: types.POLICY_VERDICT_UNKNOWN,
+}
+
+// dataSource tells where the license information of the dependencies comes from.
+func (lm LicenseMatcher) dataSource() types.DataSource {
+	if lm.LicenseDataSource == LICENSE_DATA_SOURCE_SBOM {
+		return types.DATA_SOURCE_SBOM
+	}
+	return types.DATA_SOURCE_KNOWLEDGE_BASE
+}
+
+// resolutionConfidence rates, from 0 to 1, how confident the resolution of the licenses of a dependency is.
+// Licenses declared as valid SPDX identifiers are certain, licenses that had to be normalized
+// are as confident as the least confident normalization, and nothing resolved means no confidence.
+func resolutionConfidence(resolved licenseRepository.DependencyLicenses, normalizations []types.LicenseNormalization) float64 {
+	if len(resolved.Licenses) == 0 && !resolved.Proprietary {
+		return 0
+	}
+	confidence := 1.0
+	for _, normalization := range normalizations {
+		confidence = min(confidence, normalization.Confidence)
+	}
+	return confidence
+}
+
+// unresolvedReason tells why the license of a dependency could not be resolved, from the error of its resolution.
+func unresolvedReason(err error, resolved licenseRepository.DependencyLicenses) types.UnresolvedReason {
+	switch {
+	case errors.Is(err, licenseRepository.ErrPackageNotFound):
+		return types.UNRESOLVED_REASON_PACKAGE_NOT_FOUND
+	case errors.Is(err, interpreter.ErrLicenseFile):
+		return types.UNRESOLVED_REASON_LICENSE_FILE
+	case errors.Is(err, licenseRepository.ErrLicenseEmpty), strings.TrimSpace(resolved.Declared) == "" && errors.Is(err, spdx.ErrInvalidExpression):
+		return types.UNRESOLVED_REASON_LICENSE_EMPTY
+	case errors.Is(err, spdx.ErrInvalidExpression):
+		return types.UNRESOLVED_REASON_NON_SPDX_LICENSE
+	default:
+		return types.UNRESOLVED_REASON_DATABASE_ERROR
+	}
+}
+
+func (lm LicenseMatcher) postProcessing() bool {
+	return lm.PostProcessLicenses && lm.Normalizer != nil
+}
+
+// excerpt shortens a license text to its first line, to keep the output readable.
+func excerpt(text string) string {
+	firstLine, _, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	if len(firstLine) > excerptLength {
+		return firstLine[:excerptLength] + "..."
+	}
+	return firstLine
+}
+
+// sortedKeys returns the keys of a map in increasing order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func toLicenseNormalization(result normalizer.Result) types.LicenseNormalization {
+	return types.LicenseNormalization{
+		Original:   result.Original,
+		LicenseID:  result.LicenseID,
+		Confidence: result.Confidence,
+		Method:     string(result.Method),
+	}
+}
+
+// toDeprecatedNormalizations maps the replacements of deprecated licenses onto normalizations of the dependency.
+func toDeprecatedNormalizations(replacements []spdx.Replacement) []types.LicenseNormalization {
+	normalizations := []types.LicenseNormalization{}
+	for _, replacement := range replacements {
+		normalizations = append(normalizations, types.LicenseNormalization{
+			Original:   replacement.Original,
+			LicenseID:  replacement.Replacement,
+			Confidence: 1,
+			Method:     string(normalizer.METHOD_DEPRECATED),
+		})
+	}
+	return normalizations
+}
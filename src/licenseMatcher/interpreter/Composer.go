@@ -0,0 +1,11 @@
+package interpreter
+
+import "github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+
+// composerInterpreter follows the conventions of the license field of composer.json (https://getcomposer.org/doc/04-schema.md#license):
+// the entries of the license array are alternatives, the package being distributed under the one of the licensee's choice,
+// and "proprietary" declares closed-source packages.
+var composerInterpreter = Interpreter{
+	ArrayOperator: spdx.OPERATOR_OR,
+	proprietary:   []string{"proprietary"},
+}
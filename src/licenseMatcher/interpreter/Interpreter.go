@@ -0,0 +1,62 @@
+package interpreter
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/ecosystem"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+)
+
+// METHOD_PROPRIETARY is the normalization method of the identifiers that declare a package as proprietary
+const METHOD_PROPRIETARY = "proprietary"
+
+// ErrLicenseFile is returned when a package refers to a license file that could not be matched onto an SPDX license
+var ErrLicenseFile = errors.New("license declared in a license file")
+
+// Interpreter holds the conventions the packages of an ecosystem follow to declare their license beyond SPDX expressions:
+// how the entries of a license array relate, which values declare a package as proprietary and how a package refers to a license file.
+type Interpreter struct {
+	// ArrayOperator combines the entries of a license array
+	ArrayOperator spdx.Operator
+	// proprietary holds the identifiers, in lower case, that declare a package as proprietary
+	proprietary []string
+	// fileReferences holds the prefixes, in lower case, of the declared licenses that refer to a license file
+	fileReferences []string
+}
+
+// defaultInterpreter is used for the ecosystems without conventions.
+// Since nothing tells how the entries of a license array relate, they are conservatively combined with AND.
+var defaultInterpreter = Interpreter{ArrayOperator: spdx.OPERATOR_AND}
+
+// interpreters maps every ecosystem with conventions of its own onto its interpreter
+var interpreters = map[ecosystem.Ecosystem]Interpreter{
+	ecosystem.ECOSYSTEM_NPM:       npmInterpreter,
+	ecosystem.ECOSYSTEM_PACKAGIST: composerInterpreter,
+}
+
+// For returns the interpreter of the licenses declared by the packages of an ecosystem.
+func For(dependencyEcosystem ecosystem.Ecosystem) Interpreter {
+	if interpreter, found := interpreters[dependencyEcosystem]; found {
+		return interpreter
+	}
+	return defaultInterpreter
+}
+
+// IsProprietary reports whether a license identifier declares the package as proprietary, e.g. "UNLICENSED" for npm or "proprietary" for composer.
+func (i Interpreter) IsProprietary(licenseId string) bool {
+	return slices.Contains(i.proprietary, strings.ToLower(strings.TrimSpace(licenseId)))
+}
+
+// LicenseFile returns the license file a declared license refers to, e.g. "LICENSE.md" for "SEE LICENSE IN LICENSE.md".
+// It returns false if the declared license does not refer to a license file.
+func (i Interpreter) LicenseFile(declared string) (string, bool) {
+	trimmed := strings.TrimSpace(declared)
+	for _, prefix := range i.fileReferences {
+		if len(trimmed) > len(prefix) && strings.EqualFold(trimmed[:len(prefix)], prefix) {
+			return strings.TrimSpace(trimmed[len(prefix):]), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,12 @@
+package interpreter
+
+import "github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+
+// npmInterpreter follows the conventions of the license field of package.json (https://docs.npmjs.com/cli/configuring-npm/package-json#license):
+// "UNLICENSED" declares that the package grants no right to use it, "SEE LICENSE IN <file>" refers to a custom license file
+// and the entries of the deprecated licenses array are alternatives, which npm now writes as an OR expression.
+var npmInterpreter = Interpreter{
+	ArrayOperator:  spdx.OPERATOR_OR,
+	proprietary:    []string{"unlicensed"},
+	fileReferences: []string{"see license in ", "see licence in "},
+}
@@ -0,0 +1,133 @@
+package normalizer
+
+type alias struct {
+	licenseId  string
+	confidence float64
+}
+
+// aliases maps the compact form (see compactAlias) of license names commonly found in package registries
+// onto SPDX license identifiers. Ambiguous names, such as "BSD" which may refer to several BSD variants,
+// are mapped onto the most common license with a lower confidence.
+var aliases = map[string]alias{
+	// MIT
+	"mitx11":                             {"MIT", 0.95},
+	"x11mit":                             {"MIT", 0.95},
+	"expat":                              {"MIT", 0.95},
+	"mitexpat":                           {"MIT", 0.95},
+	"mitstyle":                           {"MIT", 0.8},
+	"mitlike":                            {"MIT", 0.75},
+	"massachusettsinstituteoftechnology": {"MIT", 0.9},
+
+	// BSD
+	"bsd":           {"BSD-2-Clause", 0.6},
+	"bsdstyle":      {"BSD-2-Clause", 0.5},
+	"bsdlike":       {"BSD-2-Clause", 0.5},
+	"bsd2":          {"BSD-2-Clause", 0.95},
+	"bsd2clause":    {"BSD-2-Clause", 0.95},
+	"simplifiedbsd": {"BSD-2-Clause", 0.95},
+	"freebsd":       {"BSD-2-Clause", 0.9},
+	"bsd3":          {"BSD-3-Clause", 0.95},
+	"bsd3clause":    {"BSD-3-Clause", 0.95},
+	"newbsd":        {"BSD-3-Clause", 0.95},
+	"modifiedbsd":   {"BSD-3-Clause", 0.95},
+	"revisedbsd":    {"BSD-3-Clause", 0.95},
+	"bsd4":          {"BSD-4-Clause", 0.95},
+	"bsd4clause":    {"BSD-4-Clause", 0.95},
+	"originalbsd":   {"BSD-4-Clause", 0.9},
+	"zerobsd":       {"0BSD", 0.95},
+	"zeroclausebsd": {"0BSD", 0.95},
+
+	// Apache
+	"apache":   {"Apache-2.0", 0.8},
+	"apache2":  {"Apache-2.0", 0.95},
+	"apache20": {"Apache-2.0", 0.95},
+	"asl":      {"Apache-2.0", 0.75},
+	"asl2":     {"Apache-2.0", 0.95},
+	"asl20":    {"Apache-2.0", 0.95},
+	"apl2":     {"Apache-2.0", 0.9},
+	"apl20":    {"Apache-2.0", 0.9},
+	"apache1":  {"Apache-1.0", 0.9},
+	"apache10": {"Apache-1.0", 0.95},
+	"apache11": {"Apache-1.1", 0.95},
+
+	// GNU
+	"gpl2":     {"GPL-2.0-only", 0.9},
+	"gplv2":    {"GPL-2.0-only", 0.9},
+	"gpl20":    {"GPL-2.0-only", 0.9},
+	"gnugpl2":  {"GPL-2.0-only", 0.9},
+	"gnugplv2": {"GPL-2.0-only", 0.9},
+	"gpl3":     {"GPL-3.0-only", 0.9},
+	"gplv3":    {"GPL-3.0-only", 0.9},
+	"gpl30":    {"GPL-3.0-only", 0.9},
+	"gnugpl3":  {"GPL-3.0-only", 0.9},
+	"gnugplv3": {"GPL-3.0-only", 0.9},
+	"lgpl2":    {"LGPL-2.1-only", 0.75},
+	"lgpl21":   {"LGPL-2.1-only", 0.9},
+	"lgplv21":  {"LGPL-2.1-only", 0.9},
+	"lgpl3":    {"LGPL-3.0-only", 0.9},
+	"lgplv3":   {"LGPL-3.0-only", 0.9},
+	"lgpl30":   {"LGPL-3.0-only", 0.9},
+	"agpl3":    {"AGPL-3.0-only", 0.9},
+	"agplv3":   {"AGPL-3.0-only", 0.9},
+	"agpl30":   {"AGPL-3.0-only", 0.9},
+
+	// Mozilla, Eclipse and other weak copyleft licenses
+	"mpl":             {"MPL-2.0", 0.75},
+	"mpl11":           {"MPL-1.1", 0.95},
+	"mpl2":            {"MPL-2.0", 0.95},
+	"mpl20":           {"MPL-2.0", 0.95},
+	"mozilla20":       {"MPL-2.0", 0.9},
+	"mozillapublic20": {"MPL-2.0", 0.95},
+	"epl":             {"EPL-2.0", 0.7},
+	"epl1":            {"EPL-1.0", 0.9},
+	"epl10":           {"EPL-1.0", 0.95},
+	"epl2":            {"EPL-2.0", 0.95},
+	"epl20":           {"EPL-2.0", 0.95},
+	"eclipsepublic10": {"EPL-1.0", 0.95},
+	"eclipsepublic20": {"EPL-2.0", 0.95},
+	"cddl":            {"CDDL-1.0", 0.8},
+	"cddl10":          {"CDDL-1.0", 0.95},
+	"cddl11":          {"CDDL-1.1", 0.95},
+
+	// Others
+	"isc":             {"ISC", 1},
+	"iscstyle":        {"ISC", 0.8},
+	"unlicense":       {"Unlicense", 1},
+	"publicdomain":    {"CC0-1.0", 0.6},
+	"cc0":             {"CC0-1.0", 0.95},
+	"cc010":           {"CC0-1.0", 0.95},
+	"ccby":            {"CC-BY-4.0", 0.7},
+	"ccby30":          {"CC-BY-3.0", 0.95},
+	"ccby40":          {"CC-BY-4.0", 0.95},
+	"wtfpl":           {"WTFPL", 1},
+	"wtfpl2":          {"WTFPL", 0.95},
+	"zlib":            {"Zlib", 1},
+	"zliblibpng":      {"Zlib", 0.8},
+	"boost":           {"BSL-1.0", 0.9},
+	"bsl":             {"BSL-1.0", 0.75},
+	"bsl10":           {"BSL-1.0", 0.9},
+	"boostsoftware10": {"BSL-1.0", 0.95},
+	"artistic":        {"Artistic-2.0", 0.7},
+	"artistic2":       {"Artistic-2.0", 0.95},
+	"artistic20":      {"Artistic-2.0", 0.95},
+	"python":          {"Python-2.0", 0.7},
+	"psf":             {"Python-2.0", 0.8},
+	"python20":        {"Python-2.0", 0.95},
+	"ofl":             {"OFL-1.1", 0.75},
+	"ofl11":           {"OFL-1.1", 0.95},
+	"silofl11":        {"OFL-1.1", 0.95},
+}
+
+// notLicenses holds the compact form of values that registries accept in place of a license
+// but that do not designate any SPDX license. They must never be matched, not even fuzzily
+// ("UNLICENSED" is one edit away from "Unlicense" but means the exact opposite).
+var notLicenses = map[string]struct{}{
+	"unlicensed":  {},
+	"none":        {},
+	"nolicense":   {},
+	"proprietary": {},
+	"commercial":  {},
+	"custom":      {},
+	"other":       {},
+	"unknown":     {},
+}
@@ -0,0 +1,228 @@
+package normalizer
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+)
+
+type Method string
+
+const (
+	METHOD_CASE_INSENSITIVE Method = "case_insensitive"
+	METHOD_ALIAS            Method = "alias"
+	METHOD_DEPRECATED       Method = "deprecated"
+	METHOD_FUZZY            Method = "fuzzy"
+)
+
+// orLaterPhrases are the compact forms of the phrases that grant the later versions of a license, e.g. "GPL v2 or later"
+var orLaterPhrases = []string{"oranylaterversion", "oranylater", "orlater"}
+
+// versionPattern matches the version numbers of an identifier, e.g. "2.0" in "Apache-2.0"
+var versionPattern = regexp.MustCompile(`[0-9]+(\.[0-9]+)*`)
+
+// minimumFuzzyConfidence is the similarity below which a fuzzy match is discarded
+const minimumFuzzyConfidence = 0.85
+
+// Result describes how a non-SPDX license identifier was mapped to an SPDX license identifier.
+type Result struct {
+	Original   string
+	LicenseID  string
+	Confidence float64
+	Method     Method
+}
+
+// Normalizer maps non-SPDX license identifiers, as found in package registries, onto SPDX license identifiers.
+type Normalizer struct {
+	// licenseIds maps the lower case form of every known SPDX license identifier onto the identifier itself
+	licenseIds map[string]string
+	// fuzzyCandidates are the known SPDX license identifiers broken down into their name and versions, for fuzzy matching
+	fuzzyCandidates []fuzzyCandidate
+}
+
+// fuzzyCandidate is an SPDX license identifier broken down by splitVersions.
+type fuzzyCandidate struct {
+	licenseId string
+	name      string
+	versions  []string
+}
+
+// NewNormalizer creates a normalizer that maps identifiers onto the given SPDX license identifiers.
+func NewNormalizer(licenseIds []string) *Normalizer {
+	normalizer := Normalizer{
+		licenseIds:      map[string]string{},
+		fuzzyCandidates: []fuzzyCandidate{},
+	}
+	for _, licenseId := range licenseIds {
+		normalizer.licenseIds[strings.ToLower(licenseId)] = licenseId
+		name, versions := splitVersions(licenseId)
+		normalizer.fuzzyCandidates = append(normalizer.fuzzyCandidates, fuzzyCandidate{licenseId: licenseId, name: name, versions: versions})
+	}
+	return &normalizer
+}
+
+// Normalize maps an identifier onto an SPDX license identifier.
+// The steps are tried in order of decreasing confidence:
+//  1. case-insensitive match of an SPDX license identifier (e.g. "mit")
+//  2. deprecated SPDX license identifiers (e.g. "GPL-2.0")
+//  3. well-known aliases (e.g. "Apache 2", "MIT/X11", "GPLv3"), whose "or later" form ("GPLv3+", "GPL v3 or later")
+//     is mapped onto the "-or-later" license
+//  4. fuzzy matching on the edit distance to the name of every SPDX license identifier with the same versions
+//
+// It returns false if no SPDX license identifier matches.
+func (n *Normalizer) Normalize(identifier string) (Result, bool) {
+	trimmed := strings.TrimSpace(identifier)
+	if trimmed == "" {
+		return Result{}, false
+	}
+
+	if licenseId, ok := n.licenseIds[strings.ToLower(trimmed)]; ok {
+		return Result{Original: identifier, LicenseID: licenseId, Confidence: 1, Method: METHOD_CASE_INSENSITIVE}, true
+	}
+
+	// Deprecated identifiers that bundle an exception cannot be replaced by a single license identifier
+	if replacement, ok := spdx.ReplaceDeprecatedLicense(trimmed); ok && replacement.Exception == "" && n.known(replacement.License) {
+		return Result{Original: identifier, LicenseID: replacement.License, Confidence: 1, Method: METHOD_DEPRECATED}, true
+	}
+
+	key := compactAlias(trimmed)
+	if _, ok := notLicenses[key]; ok {
+		return Result{}, false
+	}
+	baseKey, orLater := orLaterKey(trimmed)
+	if alias, ok := aliases[baseKey]; ok {
+		licenseId := alias.licenseId
+		if orLater {
+			licenseId = orLaterLicense(licenseId)
+		}
+		if n.known(licenseId) {
+			return Result{Original: identifier, LicenseID: licenseId, Confidence: alias.confidence, Method: METHOD_ALIAS}, true
+		}
+	}
+
+	return n.fuzzy(identifier)
+}
+
+// fuzzy returns the SPDX license identifier whose name is the closest to the name of the identifier.
+// Versions are never fuzzed: only the identifiers with exactly the same versions are candidates,
+// so that an unknown version (e.g. "Apache-2.1") is left unresolved instead of being matched onto another version.
+func (n *Normalizer) fuzzy(identifier string) (Result, bool) {
+	name, versions := splitVersions(identifier)
+	if name == "" {
+		return Result{}, false
+	}
+
+	best := Result{}
+	for _, candidate := range n.fuzzyCandidates {
+		if !slices.Equal(versions, candidate.versions) {
+			continue
+		}
+		longest := max(len(name), len(candidate.name))
+		confidence := 1 - float64(levenshtein(name, candidate.name))/float64(longest)
+		// Ties are broken on the identifier to keep the result deterministic
+		if confidence > best.Confidence || (confidence == best.Confidence && candidate.licenseId < best.LicenseID) {
+			best = Result{Original: identifier, LicenseID: candidate.licenseId, Confidence: confidence, Method: METHOD_FUZZY}
+		}
+	}
+
+	if best.Confidence < minimumFuzzyConfidence {
+		return Result{}, false
+	}
+	return best, true
+}
+
+func (n *Normalizer) known(licenseId string) bool {
+	_, ok := n.licenseIds[strings.ToLower(licenseId)]
+	return ok
+}
+
+// splitVersions breaks an identifier down into the compact alias form of its name and its versions,
+// trailing ".0" parts being dropped so that "2" and "2.0" are the same version.
+func splitVersions(identifier string) (string, []string) {
+	versions := []string{}
+	for _, version := range versionPattern.FindAllString(identifier, -1) {
+		for strings.HasSuffix(version, ".0") {
+			version = strings.TrimSuffix(version, ".0")
+		}
+		versions = append(versions, version)
+	}
+	return compactAlias(versionPattern.ReplaceAllString(identifier, " ")), versions
+}
+
+// compact lower cases an identifier and strips everything but letters and digits.
+func compact(identifier string) string {
+	var builder strings.Builder
+	for _, char := range strings.ToLower(identifier) {
+		if unicode.IsLetter(char) || unicode.IsDigit(char) {
+			builder.WriteRune(char)
+		}
+	}
+	return builder.String()
+}
+
+// compactAlias computes the compact form of an identifier after dropping the filler words
+// that registries commonly add around a license name ("The MIT License", "Apache License, Version 2.0").
+func compactAlias(identifier string) string {
+	words := strings.FieldsFunc(strings.ToLower(identifier), func(char rune) bool {
+		return unicode.IsSpace(char) || char == ',' || char == '(' || char == ')'
+	})
+
+	kept := []string{}
+	for _, word := range words {
+		switch word {
+		case "the", "license", "licence", "licensed", "version", "v", "ver":
+			continue
+		}
+		kept = append(kept, word)
+	}
+	return compact(strings.Join(kept, " "))
+}
+
+// orLaterKey returns the compact alias form of an identifier without its "+" operator or the phrase granting the later versions
+// of the license, along with whether the identifier had one. compact drops the "+", which must therefore be detected first.
+func orLaterKey(identifier string) (string, bool) {
+	if base, found := strings.CutSuffix(identifier, "+"); found {
+		return compactAlias(base), true
+	}
+	key := compactAlias(identifier)
+	for _, phrase := range orLaterPhrases {
+		if base, found := strings.CutSuffix(key, phrase); found && base != "" {
+			return base, true
+		}
+	}
+	return key, false
+}
+
+// orLaterLicense returns the "-or-later" form of a license identifier that has one, e.g. "GPL-3.0-or-later" for "GPL-3.0-only".
+// Licenses without version range forms, such as Apache-2.0, are returned as they are.
+func orLaterLicense(licenseId string) string {
+	if base, found := strings.CutSuffix(licenseId, "-only"); found {
+		return base + "-or-later"
+	}
+	return licenseId
+}
+
+// levenshtein computes the edit distance between two strings.
+func levenshtein(a string, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
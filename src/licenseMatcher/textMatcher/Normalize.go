@@ -0,0 +1,115 @@
+package textMatcher
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	// commentIndicator matches the code comment markers a license text may be wrapped in (guideline 6)
+	commentIndicator = regexp.MustCompile(`^(//+|/\*+|\*+/|\*+|#+|--+|;+|%+|rem\s)\s*`)
+	// bullet matches list markers and numbering at the start of a line (guideline 7)
+	bullet = regexp.MustCompile(`^(\(?[0-9]{1,3}(\.[0-9]{1,3})*[.)]|\(?[a-zA-Z][.)]|\(?(i{1,3}|iv|v|vi{1,3}|ix|x)[.)]|[-*•·◦‣])\s+`)
+	// copyrightSymbol matches the equivalent forms of the copyright symbol (guideline 9)
+	copyrightSymbol = regexp.MustCompile(`©|\([cC]\)`)
+	// protocol matches the equivalent forms of http protocols (guideline 13)
+	protocol = regexp.MustCompile(`https?://`)
+)
+
+// NormalizeText normalizes a license text following the SPDX License Matching Guidelines,
+// so that two texts that only differ in non-substantive ways have the same normalized form:
+//   - code comment indicators, bullets and numbering at the start of lines are removed
+//   - copyright notices are removed, as they differ for every copyright holder
+//   - the text is lower cased, punctuation is dropped and whitespace is collapsed
+//   - varietal spellings are replaced by a single spelling ("licence" and "license" are equivalent)
+func NormalizeText(text string) string {
+	return strings.Join(normalizedWords(text), " ")
+}
+
+func normalizedWords(text string) []string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	kept := make([]string, 0, len(lines))
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(commentIndicator.ReplaceAllString(line, ""))
+		line = bullet.ReplaceAllString(line, "")
+		if isCopyrightNotice(line) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+
+	joined := strings.ToLower(strings.Join(kept, " "))
+	joined = protocol.ReplaceAllString(joined, "http://")
+	joined = copyrightSymbol.ReplaceAllString(joined, " copyright ")
+
+	words := strings.FieldsFunc(joined, func(char rune) bool {
+		return !unicode.IsLetter(char) && !unicode.IsDigit(char)
+	})
+	for i, word := range words {
+		if equivalent, ok := equivalentWords[word]; ok {
+			words[i] = equivalent
+		}
+	}
+	return words
+}
+
+// isCopyrightNotice reports whether a line is a copyright notice (guideline 10).
+func isCopyrightNotice(line string) bool {
+	lower := strings.ToLower(line)
+	for _, prefix := range []string{"copyright", "(c)", "©", "all rights reserved"} {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// equivalentWords maps varietal word spellings onto a single spelling (guideline 8).
+// It is a subset of the equivalent words list published with the SPDX License List.
+var equivalentWords = map[string]string{
+	"acknowledgment":  "acknowledgement",
+	"analogue":        "analog",
+	"analyse":         "analyze",
+	"artefact":        "artifact",
+	"authorisation":   "authorization",
+	"authorised":      "authorized",
+	"calibre":         "caliber",
+	"cancelled":       "canceled",
+	"capitalisations": "capitalizations",
+	"catalogue":       "catalog",
+	"categorise":      "categorize",
+	"centre":          "center",
+	"emphasised":      "emphasized",
+	"favour":          "favor",
+	"favourite":       "favorite",
+	"fulfil":          "fulfill",
+	"fulfilment":      "fulfillment",
+	"initialise":      "initialize",
+	"judgment":        "judgement",
+	"labelling":       "labeling",
+	"labour":          "labor",
+	"licence":         "license",
+	"licences":        "licenses",
+	"licenced":        "licensed",
+	"licencing":       "licensing",
+	"maximise":        "maximize",
+	"modelled":        "modeled",
+	"modelling":       "modeling",
+	"offence":         "offense",
+	"optimise":        "optimize",
+	"organisation":    "organization",
+	"organise":        "organize",
+	"practise":        "practice",
+	"programme":       "program",
+	"realise":         "realize",
+	"recognise":       "recognize",
+	"signalling":      "signaling",
+	"sublicence":      "sublicense",
+	"sublicences":     "sublicenses",
+	"utilisation":     "utilization",
+	"whilst":          "while",
+	"wilful":          "willful",
+}
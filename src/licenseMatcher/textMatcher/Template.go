@@ -0,0 +1,117 @@
+package textMatcher
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidTemplate is returned when an SPDX license template cannot be compiled.
+var ErrInvalidTemplate = errors.New("invalid SPDX license template")
+
+var (
+	// templateTag matches the <<...>> tags of an SPDX license template
+	templateTag = regexp.MustCompile(`<<(.*?)>>`)
+	// templateAttribute matches the name="value" attributes of a template tag
+	templateAttribute = regexp.MustCompile(`(\w+)="((?:[^"\\]|\\.)*)"`)
+	// matchLength matches the maximum length of a ".{0,N}" match attribute
+	matchLength = regexp.MustCompile(`^\.\{\d*,(\d+)\}$`)
+)
+
+// maximumBoundedVariableWords is the number of words above which a replaceable section is matched without bound
+const maximumBoundedVariableWords = 40
+
+// Template is a compiled SPDX license template.
+// Replaceable sections (<<var;...>>) match a bounded number of arbitrary words
+// and optional sections (<<beginOptional>>...<<endOptional>>) may be omitted.
+type Template struct {
+	pattern *regexp.Regexp
+	// text is the template in which replaceable sections are substituted by their original text
+	text string
+}
+
+// CompileTemplate compiles an SPDX license template.
+// The literal text of the template is normalized the same way license texts are normalized before matching.
+func CompileTemplate(template string) (*Template, error) {
+	pattern := strings.Builder{}
+	text := strings.Builder{}
+	depth := 0
+
+	position := 0
+	for _, location := range templateTag.FindAllStringSubmatchIndex(template, -1) {
+		literal := template[position:location[0]]
+		writeLiteral(&pattern, literal)
+		text.WriteString(literal)
+		position = location[1]
+
+		tag := template[location[2]:location[3]]
+		kind, _, _ := strings.Cut(tag, ";")
+		attributes := map[string]string{}
+		for _, attribute := range templateAttribute.FindAllStringSubmatch(tag, -1) {
+			attributes[attribute[1]] = strings.ReplaceAll(attribute[2], `\"`, `"`)
+		}
+
+		switch strings.TrimSpace(kind) {
+		case "var":
+			pattern.WriteString(variablePattern(attributes["match"]))
+			text.WriteString(" " + attributes["original"] + " ")
+		case "beginOptional":
+			pattern.WriteString("(?:")
+			depth++
+		case "endOptional":
+			if depth == 0 {
+				return nil, fmt.Errorf("%w: unbalanced optional section", ErrInvalidTemplate)
+			}
+			pattern.WriteString(")?")
+			depth--
+		default:
+			return nil, fmt.Errorf("%w: unknown tag %q", ErrInvalidTemplate, kind)
+		}
+	}
+	literal := template[position:]
+	writeLiteral(&pattern, literal)
+	text.WriteString(literal)
+
+	if depth != 0 {
+		return nil, fmt.Errorf("%w: unbalanced optional section", ErrInvalidTemplate)
+	}
+
+	compiled, err := regexp.Compile("^" + pattern.String() + "$")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTemplate, err)
+	}
+	return &Template{pattern: compiled, text: text.String()}, nil
+}
+
+// Matches reports whether the normalized words of a license text match the template.
+func (t *Template) Matches(words []string) bool {
+	return t.pattern.MatchString(strings.Join(words, " ") + " ")
+}
+
+// Text returns the license text described by the template, with the original text of every replaceable section.
+func (t *Template) Text() string {
+	return t.text
+}
+
+// writeLiteral appends the normalized words of a literal template section to the pattern.
+// Every word is followed by a single space, which is also how the matched text is joined.
+func writeLiteral(pattern *strings.Builder, literal string) {
+	for _, word := range normalizedWords(literal) {
+		pattern.WriteString(regexp.QuoteMeta(word))
+		pattern.WriteString(" ")
+	}
+}
+
+// variablePattern translates the match attribute of a replaceable section into a pattern over normalized words.
+// Since the match attribute applies to the raw text, only its maximum length is kept, approximated in words.
+func variablePattern(match string) string {
+	if groups := matchLength.FindStringSubmatch(match); groups != nil {
+		characters, err := strconv.Atoi(groups[1])
+		if err == nil && characters/3+1 <= maximumBoundedVariableWords {
+			return fmt.Sprintf(`(?:\S+ ){0,%d}`, characters/3+1)
+		}
+	}
+	return `(?:\S+ )*`
+}
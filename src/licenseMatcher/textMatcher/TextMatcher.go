@@ -0,0 +1,163 @@
+package textMatcher
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+type Method string
+
+const (
+	METHOD_HASH       Method = "text_hash"
+	METHOD_TEMPLATE   Method = "text_template"
+	METHOD_SIMILARITY Method = "text_similarity"
+)
+
+// minimumSimilarity is the similarity score below which a license text is not considered a match
+const minimumSimilarity = 0.8
+
+// Candidate is a license a text can be matched against.
+type Candidate struct {
+	LicenseID string
+	// Text is the reference text of the license
+	Text string
+	// Template is the SPDX license template of the license, if any
+	Template string
+}
+
+// Result describes the license a text was matched to.
+type Result struct {
+	LicenseID string
+	// Score is the similarity between the text and the license, 1 being an exact match
+	Score  float64
+	Method Method
+}
+
+type compiledCandidate struct {
+	licenseId string
+	template  *Template
+	bigrams   map[string]int
+	size      int
+}
+
+// TextMatcher identifies the SPDX license of a license text.
+type TextMatcher struct {
+	candidates []compiledCandidate
+	// hashes maps the hash of every normalized reference text onto its license
+	hashes map[string]string
+}
+
+// NewTextMatcher creates a text matcher for the given candidate licenses.
+// Candidates whose template cannot be compiled are matched on their text only.
+func NewTextMatcher(candidates []Candidate) *TextMatcher {
+	matcher := TextMatcher{
+		candidates: []compiledCandidate{},
+		hashes:     map[string]string{},
+	}
+
+	for _, candidate := range candidates {
+		compiled := compiledCandidate{licenseId: candidate.LicenseID}
+		if candidate.Template != "" {
+			if template, err := CompileTemplate(candidate.Template); err == nil {
+				compiled.template = template
+			}
+		}
+
+		text := candidate.Text
+		if text == "" && compiled.template != nil {
+			text = compiled.template.Text()
+		}
+		words := normalizedWords(text)
+		if len(words) == 0 {
+			continue
+		}
+
+		compiled.bigrams, compiled.size = bigrams(words)
+		hash := hashWords(words)
+		// Keep the first license when two reference texts are identical once normalized
+		if _, exists := matcher.hashes[hash]; !exists {
+			matcher.hashes[hash] = candidate.LicenseID
+		}
+		matcher.candidates = append(matcher.candidates, compiled)
+	}
+
+	return &matcher
+}
+
+// Match identifies the license of a text. The steps are tried in order:
+//  1. the hash of the normalized text equals the hash of a normalized reference text
+//  2. the normalized text matches a license template, honouring replaceable and optional sections
+//  3. the license whose reference text is the most similar, as long as the similarity reaches minimumSimilarity
+//
+// It returns false if no license matches.
+func (m *TextMatcher) Match(text string) (Result, bool) {
+	words := normalizedWords(text)
+	if len(words) == 0 {
+		return Result{}, false
+	}
+
+	if licenseId, ok := m.hashes[hashWords(words)]; ok {
+		return Result{LicenseID: licenseId, Score: 1, Method: METHOD_HASH}, true
+	}
+
+	for _, candidate := range m.candidates {
+		if candidate.template != nil && candidate.template.Matches(words) {
+			return Result{LicenseID: candidate.licenseId, Score: 1, Method: METHOD_TEMPLATE}, true
+		}
+	}
+
+	textBigrams, textSize := bigrams(words)
+	best := Result{}
+	for _, candidate := range m.candidates {
+		score := dice(textBigrams, textSize, candidate.bigrams, candidate.size)
+		// Ties are broken on the identifier to keep the result deterministic
+		if score > best.Score || (score == best.Score && candidate.licenseId < best.LicenseID) {
+			best = Result{LicenseID: candidate.licenseId, Score: score, Method: METHOD_SIMILARITY}
+		}
+	}
+
+	if best.Score < minimumSimilarity {
+		return Result{}, false
+	}
+	return best, true
+}
+
+// LooksLikeLicenseText reports whether a declared license is a license text rather than an identifier.
+// Registries such as npm accept anything in their license field, including whole license texts.
+func LooksLikeLicenseText(declared string) bool {
+	return strings.Contains(strings.TrimSpace(declared), "\n") || len(strings.Fields(declared)) >= 20
+}
+
+// bigrams counts the pairs of consecutive words of a text.
+func bigrams(words []string) (map[string]int, int) {
+	counts := map[string]int{}
+	if len(words) == 1 {
+		counts[words[0]]++
+		return counts, 1
+	}
+	for i := 0; i+1 < len(words); i++ {
+		counts[words[i]+" "+words[i+1]]++
+	}
+	return counts, len(words) - 1
+}
+
+// dice computes the Sørensen–Dice coefficient of two multisets of bigrams.
+func dice(a map[string]int, aSize int, b map[string]int, bSize int) float64 {
+	if aSize+bSize == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	shared := 0
+	for bigram, count := range a {
+		shared += min(count, b[bigram])
+	}
+	return 2 * float64(shared) / float64(aSize+bSize)
+}
+
+func hashWords(words []string) string {
+	hash := sha256.Sum256([]byte(strings.Join(words, " ")))
+	return hex.EncodeToString(hash[:])
+}
@@ -1,18 +1,38 @@
 package outputGenerator
 
 import (
+	"cmp"
+	"slices"
 	"time"
 
 	sbomTypes "github.com/CodeClarityCE/plugin-sbom-javascript/src/types/sbom/js"
+	"github.com/CodeClarityCE/plugin-sca-license/src/classification"
+	exceptionManager "github.com/CodeClarityCE/plugin-sca-license/src/exceptionManager"
+	"github.com/CodeClarityCE/plugin-sca-license/src/licenseCache"
+	"github.com/CodeClarityCE/plugin-sca-license/src/policy"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
 	types "github.com/CodeClarityCE/plugin-sca-license/src/types"
 	codeclarity "github.com/CodeClarityCE/utility-types/codeclarity_db"
-	exceptionManager "github.com/CodeClarityCE/utility-types/exceptions"
 )
 
+// DEFAULT_VERSION_SEPERATOR and DEFAULT_IMPORT_PATH_SEPERATOR are used when the SBOM does not carry its separators
+const (
+	DEFAULT_VERSION_SEPERATOR     = "@"
+	DEFAULT_IMPORT_PATH_SEPERATOR = " -> "
+)
+
+// Seperators returns the version and import path separators of an SBOM, or the default ones if it does not carry them.
+func Seperators(sbomAnalysisInfo sbomTypes.AnalysisInfo) (string, string) {
+	versionSeperator := cmp.Or(sbomAnalysisInfo.Extra.VersionSeperator, DEFAULT_VERSION_SEPERATOR)
+	importPathSeperator := cmp.Or(sbomAnalysisInfo.Extra.ImportPathSeperator, DEFAULT_IMPORT_PATH_SEPERATOR)
+	return versionSeperator, importPathSeperator
+}
+
 // SuccessOutput generates the success output for the license analysis.
-// It takes in the workspaceData, analysisStats, sbomAnalysisInfo, and start time as parameters.
+// It takes in the workspaceData, analysisStats, sbomAnalysisInfo, the errors of the analysis and start time as parameters.
 // It returns an instance of types.Output containing the workspace data, analysis information, and timing details.
-func SuccessOutput(workspaceData map[string]types.WorkSpaceLicenseInfo, analysisStats types.AnalysisStats, sbomAnalysisInfo sbomTypes.AnalysisInfo, start time.Time) types.Output {
+// The analysis fails the license policy if any violation or license incompatibility of the analysis stats is blocking.
+func SuccessOutput(workspaceData map[string]types.WorkSpaceLicenseInfo, analysisStats types.AnalysisStats, sbomAnalysisInfo sbomTypes.AnalysisInfo, analysisErrors *exceptionManager.Errors, start time.Time) types.Output {
 	output := types.Output{}
 	output.WorkSpaces = workspaceData
 	output.AnalysisInfo = types.AnalysisInfo{}
@@ -21,16 +41,22 @@ func SuccessOutput(workspaceData map[string]types.WorkSpaceLicenseInfo, analysis
 	output.AnalysisInfo.AnalysisStartTime = formattedStart
 	output.AnalysisInfo.AnalysisEndTime = formattedEnd
 	output.AnalysisInfo.AnalysisDeltaTime = delta
-	output.AnalysisInfo.Errors = exceptionManager.GetErrors()
+	output.AnalysisInfo.Errors = analysisErrors.GetErrors()
 	output.AnalysisInfo.AnalysisStats = analysisStats
+	output.AnalysisInfo.VersionSeperator, output.AnalysisInfo.ImportPathSeperator = Seperators(sbomAnalysisInfo)
+	output.AnalysisInfo.DeprecatedLicensesVersion = spdx.DEPRECATED_LICENSES_VERSION
+	output.AnalysisInfo.Verdict = types.VERDICT_PASS
+	if analysisStats.PolicyViolationDist[string(policy.SEVERITY_BLOCK)] > 0 || analysisStats.LicenseIncompatibilityDist[string(policy.SEVERITY_BLOCK)] > 0 {
+		output.AnalysisInfo.Verdict = types.VERDICT_FAIL
+	}
 	return output
 }
 
 // FailureOutput generates an output object for a failed analysis.
-// It takes the sbomAnalysisInfo pointer and the start time as parameters.
-// It returns an output object with the analysis status set to FAILURE.
+// It takes the sbomAnalysisInfo pointer, the errors of the analysis and the start time as parameters.
+// It returns an output object with the analysis status set to FAILURE, a failed analysis does not pass the license policy.
 // The output object includes workspace data, analysis information, and error details.
-func FailureOutput(sbomAnalysisInfo sbomTypes.AnalysisInfo, start time.Time) types.Output {
+func FailureOutput(sbomAnalysisInfo sbomTypes.AnalysisInfo, analysisErrors *exceptionManager.Errors, start time.Time) types.Output {
 	output := types.Output{}
 	output.AnalysisInfo.Status = codeclarity.FAILURE
 	workspaceData := map[string]types.WorkSpaceLicenseInfo{}
@@ -41,7 +67,9 @@ func FailureOutput(sbomAnalysisInfo sbomTypes.AnalysisInfo, start time.Time) typ
 	output.AnalysisInfo.AnalysisStartTime = formattedStart
 	output.AnalysisInfo.AnalysisEndTime = formattedEnd
 	output.AnalysisInfo.AnalysisDeltaTime = delta
-	output.AnalysisInfo.Errors = exceptionManager.GetErrors()
+	output.AnalysisInfo.Errors = analysisErrors.GetErrors()
+	output.AnalysisInfo.DeprecatedLicensesVersion = spdx.DEPRECATED_LICENSES_VERSION
+	output.AnalysisInfo.Verdict = types.VERDICT_FAIL
 
 	return output
 }
@@ -49,28 +77,69 @@ func FailureOutput(sbomAnalysisInfo sbomTypes.AnalysisInfo, start time.Time) typ
 // GenerateAnalysisStats calculates the analysis statistics based on the provided workspace data.
 // It takes a map of workspace data, where the keys are workspace names and the values are pointers to WorkSpaceLicenseInfoInternal structs.
 // The function iterates over the workspace data and counts the number of SPDX licenses, non-SPDX licenses, copy left licenses, and permissive licenses.
-// It also generates a distribution map of licenses, where the keys are license names and the values are the number of occurrences.
+// Copy left and permissive licenses are told apart by the classification package, public domain dedications count as permissive.
+// It also counts the dependencies whose license could not be looked up because the knowledge base failed to answer.
+// It also generates a distribution map of licenses, where the keys are license names and the values are the number of occurrences,
+// and a distribution map of license categories, where the values are the number of dependencies licensed under at least one license of the category.
+// Active policy violations are counted per severity, every severity being present in the distribution map, waived violations are counted apart.
+// License incompatibilities are counted per severity in the same way.
 // The function returns an AnalysisStats struct containing the calculated statistics.
 func GenerateAnalysisStats(workspaceData map[string]types.WorkSpaceLicenseInfoInternal) types.AnalysisStats {
 
 	numberOfSpdxLicenses := 0
 	numberOfNonSpdxLicenses := 0
-
-	// Place holders for now
-	// We do not yet have information on whether a license is copy left or permissive
+	numberOfLookupErrors := 0
+	numberOfProprietaryDeps := 0
 	numberOfCopyLeftLicenses := 0
 	numberOfPermissiveLicenses := 0
+	numberOfWaivedViolations := 0
 
 	licensesDist := map[string]int{}
+	categoryDist := map[string]int{}
+	violationDist := map[string]int{}
+	incompatibilityDist := map[string]int{}
+	for _, severity := range policy.Severities {
+		violationDist[string(severity)] = 0
+		incompatibilityDist[string(severity)] = 0
+	}
 
 	for _, workSpaceLicenseInfo := range workspaceData {
 
 		for licenseKey, val := range workSpaceLicenseInfo.LicensesDepMap {
 			licensesDist[licenseKey] += len(val)
+
+			category := classification.Classify(licenseKey)
+			if category.IsCopyleft() {
+				numberOfCopyLeftLicenses++
+			} else if category.IsPermissive() {
+				numberOfPermissiveLicenses++
+			}
 		}
 
 		numberOfSpdxLicenses += len(workSpaceLicenseInfo.LicensesDepMap)
 		numberOfNonSpdxLicenses += len(workSpaceLicenseInfo.NonSpdxLicensesDepMap)
+		numberOfProprietaryDeps += len(workSpaceLicenseInfo.ProprietaryDependencies)
+
+		for _, violation := range workSpaceLicenseInfo.PolicyViolations {
+			if violation.Status == types.VIOLATION_STATUS_WAIVED {
+				numberOfWaivedViolations++
+				continue
+			}
+			violationDist[string(violation.Severity)]++
+		}
+
+		for _, incompatibility := range workSpaceLicenseInfo.LicenseIncompatibilities {
+			incompatibilityDist[string(incompatibility.Severity)]++
+		}
+
+		for _, dependencyInfo := range workSpaceLicenseInfo.DependencyInfo {
+			if dependencyInfo.ResolutionStatus == types.RESOLUTION_STATUS_ERROR {
+				numberOfLookupErrors++
+			}
+			for _, category := range dependencyCategories(dependencyInfo) {
+				categoryDist[string(category)]++
+			}
+		}
 
 	}
 
@@ -79,11 +148,56 @@ func GenerateAnalysisStats(workspaceData map[string]types.WorkSpaceLicenseInfoIn
 		NumberOfNonSpdxLicenses:    numberOfNonSpdxLicenses,
 		NumberOfCopyLeftLicenses:   numberOfCopyLeftLicenses,
 		NumberOfPermissiveLicenses: numberOfPermissiveLicenses,
+		NumberOfLookupErrors:       numberOfLookupErrors,
+		NumberOfProprietaryDeps:    numberOfProprietaryDeps,
 		LicenseDist:                licensesDist,
+		LicenseCategoryDist:        categoryDist,
+		PolicyViolationDist:        violationDist,
+		NumberOfWaivedViolations:   numberOfWaivedViolations,
+		LicenseIncompatibilityDist: incompatibilityDist,
 	}
 
 }
 
+// dependencyCategories returns the categories of the licenses of a dependency, each category once.
+// Licenses that could not be resolved to SPDX licenses, as well as dependencies without any license, fall in the unknown category.
+func dependencyCategories(dependencyInfo types.DependencyInfo) []classification.Category {
+	categories := []classification.Category{}
+	seen := map[classification.Category]bool{}
+	add := func(category classification.Category) {
+		if !seen[category] {
+			seen[category] = true
+			categories = append(categories, category)
+		}
+	}
+
+	for _, leaf := range dependencyInfo.Expression.Leaves() {
+		if slices.Contains(dependencyInfo.NonSpdxLicenses, leaf.License) {
+			add(classification.CATEGORY_UNKNOWN)
+			continue
+		}
+		add(classification.ClassifyLicense(leaf))
+	}
+	if len(categories) == 0 {
+		add(classification.CATEGORY_UNKNOWN)
+	}
+	return categories
+}
+
+// GenerateCacheStats reports the cache hits and misses recorded during an analysis,
+// along with the number of dependencies held by the cache, if any.
+func GenerateCacheStats(recorder *licenseCache.Recorder, cache *licenseCache.LicenseCache) types.CacheStats {
+	stats := recorder.Stats()
+	cacheStats := types.CacheStats{
+		Hits:   stats.Hits,
+		Misses: stats.Misses,
+	}
+	if cache != nil {
+		cacheStats.Entries = cache.Len()
+	}
+	return cacheStats
+}
+
 // getAnalysisTiming calculates the analysis timing by measuring the elapsed time between the start time and the current time.
 // It returns the start time, end time, and elapsed time in seconds.
 func getAnalysisTiming(start time.Time) (string, string, float64) {
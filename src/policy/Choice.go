@@ -0,0 +1,60 @@
+package policy
+
+import (
+	"slices"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/classification"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+)
+
+// choose resolves a disjunction from the results of its alternatives.
+// Among the allowed alternatives, the least restrictive one is elected, e.g. MIT for "MIT OR GPL-3.0-or-later",
+// the first one winning a tie. An alternative is as restrictive as the most restrictive category of its licenses.
+// Without any allowed alternative, the disjunction is unknown if one of its alternatives is unknown.
+// Otherwise no choice satisfies the policy: the disjunction is denied by a single decision on the full expression,
+// since no alternative is the one the dependency is used under. Its severity is the lowest severity of its alternatives
+// since the least severe alternative can still be chosen.
+func (p Policy) choose(expression spdx.Expression, alternatives []Result) Result {
+	var elected *Result
+	for index, alternative := range alternatives {
+		if alternative.Outcome != OUTCOME_ALLOWED {
+			continue
+		}
+		if elected == nil || restrictiveness(alternative.Elected) < restrictiveness(elected.Elected) {
+			elected = &alternatives[index]
+		}
+	}
+	if elected != nil {
+		return *elected
+	}
+
+	if slices.ContainsFunc(alternatives, func(alternative Result) bool { return alternative.Outcome == OUTCOME_UNKNOWN }) {
+		return combine(alternatives, OUTCOME_UNKNOWN)
+	}
+
+	decision := Decision{
+		License:      expression.String(),
+		Expression:   expression.String(),
+		Outcome:      OUTCOME_DENIED,
+		Rule:         Rule{Kind: RULE_NO_SATISFYING_CHOICE},
+		Alternatives: []Decision{},
+	}
+	for _, alternative := range alternatives {
+		for _, denied := range alternative.Denied() {
+			decision.Alternatives = append(decision.Alternatives, denied)
+			if decision.Severity == "" || slices.Index(Severities, denied.Severity) < slices.Index(Severities, decision.Severity) {
+				decision.Severity = denied.Severity
+			}
+		}
+	}
+	return Result{Outcome: OUTCOME_DENIED, Decisions: []Decision{decision}}
+}
+
+// restrictiveness rates how restrictive the licenses of an expression are, as the rank of their most restrictive category.
+func restrictiveness(expression spdx.Expression) int {
+	rank := 0
+	for _, leaf := range expression.Leaves() {
+		rank = max(rank, slices.Index(classification.Categories, classification.ClassifyLicense(leaf)))
+	}
+	return rank
+}
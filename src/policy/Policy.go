@@ -0,0 +1,285 @@
+package policy
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/classification"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+)
+
+// Mode tells whether licenses that no rule mentions are allowed or denied.
+type Mode string
+
+const (
+	// Every license is allowed, unless a rule denies it
+	MODE_DENYLIST Mode = "denylist"
+	// Every license is denied, unless a rule allows it
+	MODE_ALLOWLIST Mode = "allowlist"
+)
+
+// UnknownHandling tells how licenses that are not SPDX licenses, or that could not be determined, are evaluated.
+type UnknownHandling string
+
+const (
+	// Unknown licenses need a review, they are neither allowed nor denied
+	UNKNOWN_REVIEW UnknownHandling = "review"
+	UNKNOWN_ALLOW  UnknownHandling = "allow"
+	UNKNOWN_DENY   UnknownHandling = "deny"
+)
+
+// Severity tells how serious the violation of a rule is.
+type Severity string
+
+const (
+	// The violation is reported for information only
+	SEVERITY_INFO Severity = "info"
+	// The violation needs a review, e.g. by the legal team
+	SEVERITY_WARN Severity = "warn"
+	// The violation is a hard stop, it fails the analysis
+	SEVERITY_BLOCK Severity = "block"
+)
+
+// Severities lists the severities from the least to the most serious
+var Severities = []Severity{SEVERITY_INFO, SEVERITY_WARN, SEVERITY_BLOCK}
+
+// Policy is the set of rules dependency licenses are evaluated against.
+// Rules on license identifiers take precedence over rules on categories, and denials take precedence over allowances.
+// A rule on a license covers its version range (e.g. "GPL-2.0-or-later" covers GPL-3.0-only), and a rule that names
+// an exception (e.g. "GPL-2.0-only WITH Classpath-exception-2.0") only covers the license with that exception.
+type Policy struct {
+	Mode            Mode
+	AllowedLicenses []string
+	DeniedLicenses  []string
+	// AllowedExceptions lift the denial of the licenses they are attached to, unless a rule denies the license with its exception
+	AllowedExceptions []string
+	AllowedCategories []classification.Category
+	DeniedCategories  []classification.Category
+	UnknownLicenses   UnknownHandling
+	// DefaultSeverity is the severity of the violations of the rules Severities does not mention
+	DefaultSeverity Severity
+	// Severities sets the severity of the violations of specific rules. It is keyed by rule (e.g. "denied_license:MPL-2.0"),
+	// by the license or category of a rule (e.g. "MPL-2.0" or "weak_copyleft") or by kind of rule (e.g. "unknown_license").
+	Severities map[string]Severity
+	// Waivers approve the violations of specific packages
+	Waivers []Waiver
+}
+
+// Default returns the policy of an analysis that does not configure any rule: every known license is allowed.
+func Default() Policy {
+	return Policy{
+		Mode:              MODE_DENYLIST,
+		AllowedLicenses:   []string{},
+		DeniedLicenses:    []string{},
+		AllowedExceptions: []string{},
+		AllowedCategories: []classification.Category{},
+		DeniedCategories:  []classification.Category{},
+		UnknownLicenses:   UNKNOWN_REVIEW,
+		DefaultSeverity:   SEVERITY_BLOCK,
+		Severities:        map[string]Severity{},
+		Waivers:           []Waiver{},
+	}
+}
+
+// ParseMode parses the name of a policy mode.
+// It returns false if the name is not a mode.
+func ParseMode(name string) (Mode, bool) {
+	mode := Mode(name)
+	return mode, mode == MODE_DENYLIST || mode == MODE_ALLOWLIST
+}
+
+// ParseUnknownHandling parses the name of the handling of unknown licenses.
+// It returns false if the name is not a handling.
+func ParseUnknownHandling(name string) (UnknownHandling, bool) {
+	handling := UnknownHandling(name)
+	return handling, handling == UNKNOWN_REVIEW || handling == UNKNOWN_ALLOW || handling == UNKNOWN_DENY
+}
+
+// ParseSeverity parses the name of a severity.
+// It returns false if the name is not a severity.
+func ParseSeverity(name string) (Severity, bool) {
+	severity := Severity(name)
+	return severity, slices.Contains(Severities, severity)
+}
+
+// Severity returns the severity of the violations of a rule.
+// The most specific entry of Severities wins: the rule itself, then its license or category, then its kind.
+func (p Policy) Severity(rule Rule) Severity {
+	return p.severity(rule.String(), rule.Value, string(rule.Kind))
+}
+
+// licenseSeverity returns the severity of the violation of a license by a rule.
+// The entries of the license and of its category are looked up along with those of the rule, most specific first,
+// so that they also apply to the rules that name neither a license nor a category, such as the rule of an allowlist.
+func (p Policy) licenseSeverity(rule Rule, license spdx.Expression) Severity {
+	return p.severity(rule.String(), license.License, rule.Value, string(classification.ClassifyLicense(license)), string(rule.Kind))
+}
+
+// severity returns the severity of the first key found in Severities, or the default severity.
+func (p Policy) severity(keys ...string) Severity {
+	for _, key := range keys {
+		if severity, found := p.Severities[key]; found && key != "" {
+			return severity
+		}
+	}
+	if p.DefaultSeverity == "" {
+		return SEVERITY_BLOCK
+	}
+	return p.DefaultSeverity
+}
+
+// Evaluate evaluates a license expression against the policy.
+// known tells whether a license identifier of the expression is an SPDX license, the others are evaluated as unknown licenses.
+// A conjunction is denied if any of its terms is denied, a disjunction is allowed if any of its alternatives is allowed,
+// in which case the alternative the dependency is used under is elected (see choose).
+func (p Policy) Evaluate(expression spdx.Expression, known func(licenseId string) bool) Result {
+	if expression.IsEmpty() {
+		return p.EvaluateUnknown("")
+	}
+	if expression.IsLeaf() {
+		decision, elected := p.evaluateLicense(expression, known(expression.License))
+		result := Result{Outcome: decision.Outcome, Decisions: []Decision{decision}}
+		if result.Outcome == OUTCOME_ALLOWED {
+			result.Elected = elected
+		}
+		return result
+	}
+
+	results := []Result{}
+	for _, term := range expression.Terms {
+		results = append(results, p.Evaluate(term, known))
+	}
+
+	if expression.Operator == spdx.OPERATOR_OR {
+		return p.choose(expression, results)
+	}
+
+	result := combine(results, OUTCOME_DENIED, OUTCOME_UNKNOWN, OUTCOME_ALLOWED)
+	if result.Outcome == OUTCOME_ALLOWED {
+		elected := []spdx.Expression{}
+		for _, term := range results {
+			elected = append(elected, term.Elected)
+		}
+		result.Elected = spdx.Join(spdx.OPERATOR_AND, elected...)
+	}
+	return result
+}
+
+// EvaluateUnknown evaluates a dependency whose license could not be determined, declared is the license it declares, if any.
+func (p Policy) EvaluateUnknown(declared string) Result {
+	decision, _ := p.evaluateLicense(spdx.NewLicense(declared), false)
+	return Result{Outcome: decision.Outcome, Decisions: []Decision{decision}}
+}
+
+// evaluateLicense evaluates a single license of an expression, along with the license it is elected to be used under.
+// A license whose version range grants several versions (e.g. "GPL-2.0-or-later") is allowed if one of these versions is allowed,
+// it is then used under the version it declares if allowed, or else under the first allowed later version (e.g. "GPL-3.0-only").
+// When no version is allowed, the decision on the version it declares is returned.
+func (p Policy) evaluateLicense(license spdx.Expression, known bool) (Decision, spdx.Expression) {
+	if !known {
+		decision := Decision{License: license.String(), Rule: Rule{Kind: RULE_UNKNOWN_LICENSE, Value: license.License}}
+		switch p.UnknownLicenses {
+		case UNKNOWN_ALLOW:
+			decision.Outcome = OUTCOME_ALLOWED
+		case UNKNOWN_DENY:
+			decision.Outcome, decision.Severity = OUTCOME_DENIED, p.licenseSeverity(decision.Rule, license)
+		default:
+			decision.Outcome = OUTCOME_UNKNOWN
+		}
+		return decision, license
+	}
+
+	var first Decision
+	for index, version := range spdx.ParseLicense(license).Versions() {
+		decision := p.evaluateVersion(version)
+		decision.License = license.String()
+		if decision.Outcome == OUTCOME_ALLOWED {
+			if index == 0 {
+				return decision, license
+			}
+			return decision, version
+		}
+		if index == 0 {
+			first = decision
+		}
+	}
+	return first, spdx.Expression{}
+}
+
+// evaluateVersion evaluates a single version of a license, with its exception if any.
+// Rules naming the exception of the license come first, then the allowed exceptions, rules on the license itself and rules on its category.
+func (p Policy) evaluateVersion(license spdx.Expression) Decision {
+	decision := Decision{}
+
+	parsed := spdx.ParseLicense(license)
+	category := classification.ClassifyLicense(license)
+	deniedWithException, deniedWithExceptionFound := matchLicense(p.DeniedLicenses, license, true)
+	allowedWithException, allowedWithExceptionFound := matchLicense(p.AllowedLicenses, license, true)
+	denied, deniedFound := matchLicense(p.DeniedLicenses, license, false)
+	allowed, allowedFound := matchLicense(p.AllowedLicenses, license, false)
+	switch {
+	case deniedWithExceptionFound:
+		decision.Outcome, decision.Rule = OUTCOME_DENIED, Rule{Kind: RULE_DENIED_LICENSE, Value: deniedWithException}
+	case allowedWithExceptionFound:
+		decision.Outcome, decision.Rule = OUTCOME_ALLOWED, Rule{Kind: RULE_ALLOWED_LICENSE, Value: allowedWithException}
+	case parsed.Exception != "" && slices.ContainsFunc(p.AllowedExceptions, func(exception string) bool { return strings.EqualFold(exception, parsed.Exception) }):
+		decision.Outcome, decision.Rule = OUTCOME_ALLOWED, Rule{Kind: RULE_ALLOWED_EXCEPTION, Value: parsed.Exception}
+	case deniedFound:
+		decision.Outcome, decision.Rule = OUTCOME_DENIED, Rule{Kind: RULE_DENIED_LICENSE, Value: denied}
+	case allowedFound:
+		decision.Outcome, decision.Rule = OUTCOME_ALLOWED, Rule{Kind: RULE_ALLOWED_LICENSE, Value: allowed}
+	case slices.Contains(p.DeniedCategories, category):
+		decision.Outcome, decision.Rule = OUTCOME_DENIED, Rule{Kind: RULE_DENIED_CATEGORY, Value: string(category)}
+	case slices.Contains(p.AllowedCategories, category):
+		decision.Outcome, decision.Rule = OUTCOME_ALLOWED, Rule{Kind: RULE_ALLOWED_CATEGORY, Value: string(category)}
+	case p.Mode == MODE_ALLOWLIST:
+		decision.Outcome, decision.Rule = OUTCOME_DENIED, Rule{Kind: RULE_NOT_ALLOWED}
+	default:
+		decision.Outcome, decision.Rule = OUTCOME_ALLOWED, Rule{Kind: RULE_NOT_DENIED}
+	}
+	if decision.Outcome == OUTCOME_DENIED {
+		decision.Severity = p.licenseSeverity(decision.Rule, license)
+	}
+	return decision
+}
+
+// matchLicense returns the first license of a rule that covers a version of a license.
+// withException selects the licenses of the rule that name the exception of the version, the other ones are only matched
+// against the license itself. Licenses of the rule that are not valid SPDX identifiers only match the identical license.
+func matchLicense(licenses []string, version spdx.Expression, withException bool) (string, bool) {
+	parsed := spdx.ParseLicense(version)
+	for _, license := range licenses {
+		leaf, err := spdx.Parse(license)
+		if err != nil || !leaf.IsLeaf() {
+			if !withException && license == version.License {
+				return license, true
+			}
+			continue
+		}
+		rule := spdx.ParseLicense(leaf)
+		if withException != (rule.Exception != "") || (withException && !strings.EqualFold(rule.Exception, parsed.Exception)) {
+			continue
+		}
+		if rule.Contains(parsed) {
+			return license, true
+		}
+	}
+	return "", false
+}
+
+// combine returns the first outcome of precedence that one of the results has,
+// along with the decisions of the results that have this outcome.
+func combine(results []Result, precedence ...Outcome) Result {
+	for _, outcome := range precedence {
+		combined := Result{Outcome: outcome, Decisions: []Decision{}}
+		for _, result := range results {
+			if result.Outcome == outcome {
+				combined.Decisions = append(combined.Decisions, result.Decisions...)
+			}
+		}
+		if len(combined.Decisions) > 0 {
+			return combined
+		}
+	}
+	return Result{Outcome: precedence[len(precedence)-1], Decisions: []Decision{}}
+}
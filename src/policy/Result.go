@@ -0,0 +1,84 @@
+package policy
+
+import "github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+
+// Outcome is the result of the evaluation of a license, or of a license expression, against a policy.
+type Outcome string
+
+const (
+	OUTCOME_ALLOWED Outcome = "allowed"
+	OUTCOME_DENIED  Outcome = "denied"
+	// The license is unknown and the policy requires unknown licenses to be reviewed
+	OUTCOME_UNKNOWN Outcome = "unknown"
+)
+
+// RuleKind identifies the kind of rule of a policy that decided the outcome of a license.
+type RuleKind string
+
+const (
+	RULE_ALLOWED_LICENSE  RuleKind = "allowed_license"
+	RULE_DENIED_LICENSE   RuleKind = "denied_license"
+	RULE_ALLOWED_CATEGORY RuleKind = "allowed_category"
+	RULE_DENIED_CATEGORY  RuleKind = "denied_category"
+	// The exception attached to the license is allowed
+	RULE_ALLOWED_EXCEPTION RuleKind = "allowed_exception"
+	// The license is not allowed by any rule of an allowlist policy
+	RULE_NOT_ALLOWED RuleKind = "not_allowed"
+	// The license is not denied by any rule of a denylist policy
+	RULE_NOT_DENIED      RuleKind = "not_denied"
+	RULE_UNKNOWN_LICENSE RuleKind = "unknown_license"
+	// Every alternative of a disjunction is denied, the decisions of the alternatives tell why
+	RULE_NO_SATISFYING_CHOICE RuleKind = "no_satisfying_choice"
+	// The license is not compatible with the license of the project, used to rate the severity of license incompatibilities
+	RULE_INCOMPATIBLE_LICENSE RuleKind = "incompatible_license"
+)
+
+// Rule is the rule of a policy that decided the outcome of a license.
+type Rule struct {
+	Kind RuleKind `json:"kind"`
+	// Value is the license or category the rule is about, if any
+	Value string `json:"value,omitempty"`
+}
+
+// String formats the rule as its kind followed by its value, e.g. "denied_category:strong_copyleft".
+func (r Rule) String() string {
+	if r.Value == "" {
+		return string(r.Kind)
+	}
+	return string(r.Kind) + ":" + r.Value
+}
+
+// Decision is the outcome of a single license of an expression, along with the rule that decided it.
+type Decision struct {
+	License string  `json:"license"`
+	Outcome Outcome `json:"outcome"`
+	Rule    Rule    `json:"rule"`
+	// Severity is the severity of the violation, for denied licenses only
+	Severity Severity `json:"severity,omitempty"`
+	// Expression is the full disjunction a RULE_NO_SATISFYING_CHOICE decision denies, waivers are matched against it
+	Expression string `json:"expression,omitempty"`
+	// Alternatives are the decisions of the alternatives of a disjunction without any satisfying choice
+	Alternatives []Decision `json:"alternatives,omitempty"`
+}
+
+// Result is the outcome of a license expression.
+// Decisions holds the decisions of the licenses the outcome depends on:
+// the denied licenses of a denied expression, the licenses of the allowed alternative of an allowed expression.
+type Result struct {
+	Outcome   Outcome
+	Decisions []Decision
+	// Elected is the expression the dependency is used under, every disjunction being replaced by its elected alternative.
+	// It is only set on allowed results.
+	Elected spdx.Expression
+}
+
+// Denied returns the decisions of the result that deny a license.
+func (r Result) Denied() []Decision {
+	denied := []Decision{}
+	for _, decision := range r.Decisions {
+		if decision.Outcome == OUTCOME_DENIED {
+			denied = append(denied, decision)
+		}
+	}
+	return denied
+}
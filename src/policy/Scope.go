@@ -0,0 +1,44 @@
+package policy
+
+// ScopeMode tells which dependencies are evaluated against which policy.
+type ScopeMode string
+
+const (
+	// Only runtime dependencies are evaluated, dev dependencies are reported but not evaluated
+	SCOPE_MODE_RUNTIME ScopeMode = "runtime"
+	// Every dependency is evaluated against the same policy
+	SCOPE_MODE_ALL ScopeMode = "all"
+	// Runtime and dev dependencies are evaluated against their own policy
+	SCOPE_MODE_SEPARATE ScopeMode = "separate"
+)
+
+// ParseScopeMode parses the name of a scope mode.
+// It returns false if the name is not a scope mode.
+func ParseScopeMode(name string) (ScopeMode, bool) {
+	mode := ScopeMode(name)
+	return mode, mode == SCOPE_MODE_RUNTIME || mode == SCOPE_MODE_ALL || mode == SCOPE_MODE_SEPARATE
+}
+
+// ScopedPolicy holds the policies of the runtime and of the dev dependencies of a project.
+type ScopedPolicy struct {
+	Mode    ScopeMode
+	Runtime Policy
+	// Dev is the policy of the dev dependencies, only used in SCOPE_MODE_SEPARATE
+	Dev Policy
+}
+
+// For returns the policy a dependency is evaluated against, dev tells whether it is only needed for development.
+// It returns false if the dependency is not evaluated at all.
+func (s ScopedPolicy) For(dev bool) (Policy, bool) {
+	if !dev {
+		return s.Runtime, true
+	}
+	switch s.Mode {
+	case SCOPE_MODE_RUNTIME:
+		return Policy{}, false
+	case SCOPE_MODE_SEPARATE:
+		return s.Dev, true
+	default:
+		return s.Runtime, true
+	}
+}
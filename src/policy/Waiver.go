@@ -0,0 +1,87 @@
+package policy
+
+import (
+	"slices"
+	"time"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/semver"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+)
+
+// Waiver approves the violations of the policy by a package, e.g. a GPL build tool that is never shipped.
+// Empty criteria match anything, a waiver without criteria other than its package waives every violation of the package.
+type Waiver struct {
+	Package string `json:"package"`
+	// Versions is the range of versions of the package the waiver applies to, nil for every version
+	Versions *semver.Range `json:"-"`
+	// VersionRange is Versions as written in the configuration
+	VersionRange string `json:"versions,omitempty"`
+	Workspace    string `json:"workspace,omitempty"`
+	// License is the denied license the waiver applies to, as reported in the violations (e.g. "GPL-3.0-only")
+	License       string `json:"license,omitempty"`
+	Justification string `json:"justification"`
+	// Expires is the instant the waiver stops applying, its violations are then reported again
+	Expires time.Time `json:"expires"`
+}
+
+// Matches reports whether the waiver applies to the violation of a license by a version of a package of a workspace.
+// expression is the disjunction of a violation without any satisfying choice, empty otherwise (see Decision.Expression).
+// Versions that are not semantic versions only match waivers without a version range.
+func (w Waiver) Matches(workspace string, name string, version string, license string, expression string) bool {
+	if w.Package != name || (w.Workspace != "" && w.Workspace != workspace) || !w.matchesLicense(license, expression) {
+		return false
+	}
+	if w.Versions == nil {
+		return true
+	}
+	parsed, err := semver.Parse(version)
+	return err == nil && w.Versions.Contains(parsed)
+}
+
+// matchesLicense reports whether the license of the waiver is the denied license.
+// A violation of a disjunction is also waived by a waiver of the full disjunction, however it is written,
+// or of one of its licenses, under which the dependency can then be used.
+func (w Waiver) matchesLicense(license string, expression string) bool {
+	if w.License == "" || w.License == license {
+		return true
+	}
+	if expression == "" {
+		return false
+	}
+	denied, err := spdx.Parse(expression)
+	if err != nil {
+		return false
+	}
+	waived, err := spdx.Parse(w.License)
+	if err != nil {
+		return false
+	}
+	return waived.String() == denied.String() || (waived.IsLeaf() && slices.Contains(denied.Licenses(), waived.License))
+}
+
+// ExpiredAt reports whether the waiver no longer applies at the given instant.
+func (w Waiver) ExpiredAt(at time.Time) bool {
+	return !at.Before(w.Expires)
+}
+
+// FindWaiver returns the waiver of the policy that applies to the violation of a license by a version of a package of a workspace.
+// Waivers that are still valid at the given instant are preferred, an expired waiver is only returned when no other one matches,
+// so that its expiry can be reported. It returns false if no waiver matches.
+func (p Policy) FindWaiver(workspace string, name string, version string, license string, expression string, at time.Time) (Waiver, bool) {
+	var expired *Waiver
+	for index, waiver := range p.Waivers {
+		if !waiver.Matches(workspace, name, version, license, expression) {
+			continue
+		}
+		if !waiver.ExpiredAt(at) {
+			return waiver, true
+		}
+		if expired == nil {
+			expired = &p.Waivers[index]
+		}
+	}
+	if expired != nil {
+		return *expired, true
+	}
+	return Waiver{}, false
+}
@@ -0,0 +1,221 @@
+package purl
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidPurl is returned when a string is not a valid package URL.
+var ErrInvalidPurl = errors.New("invalid package URL")
+
+const scheme = "pkg:"
+
+// Types whose namespace and name are case insensitive, and therefore lowercased in their canonical form
+var caseInsensitiveTypes = map[string]bool{
+	"npm":      true,
+	"composer": true,
+}
+
+// PackageURL is a package URL, as described in https://github.com/package-url/purl-spec.
+// It identifies a package regardless of the ecosystem it belongs to, e.g. pkg:npm/%40babel/core@7.0.0.
+type PackageURL struct {
+	Type string
+	// Namespace is the scope, group or vendor of the package, if any (e.g. "@babel" or "symfony")
+	Namespace  string
+	Name       string
+	Version    string
+	Qualifiers map[string]string
+	Subpath    string
+}
+
+// New creates the package URL of a package version.
+// Names that contain a "/", such as scoped npm packages or composer packages, are split into a namespace and a name.
+func New(purlType string, name string, version string) PackageURL {
+	namespace := ""
+	if index := strings.LastIndex(name, "/"); index >= 0 {
+		namespace, name = name[:index], name[index+1:]
+	}
+	return PackageURL{Type: purlType, Namespace: namespace, Name: name, Version: version}
+}
+
+// String formats the package URL in its canonical form.
+// The type is lowercased, the namespace and name are lowercased for case insensitive types,
+// and every component is percent-encoded.
+func (p PackageURL) String() string {
+	purlType := strings.ToLower(p.Type)
+	namespace, name := p.Namespace, p.Name
+	if caseInsensitiveTypes[purlType] {
+		namespace, name = strings.ToLower(namespace), strings.ToLower(name)
+	}
+
+	builder := strings.Builder{}
+	builder.WriteString(scheme)
+	builder.WriteString(purlType)
+	builder.WriteString("/")
+	for _, segment := range strings.Split(namespace, "/") {
+		if segment != "" {
+			builder.WriteString(escape(segment))
+			builder.WriteString("/")
+		}
+	}
+	builder.WriteString(escape(name))
+	if p.Version != "" {
+		builder.WriteString("@")
+		builder.WriteString(escape(p.Version))
+	}
+	if len(p.Qualifiers) > 0 {
+		qualifiers := []string{}
+		for _, key := range slices.Sorted(maps.Keys(p.Qualifiers)) {
+			if p.Qualifiers[key] != "" {
+				qualifiers = append(qualifiers, strings.ToLower(key)+"="+escape(p.Qualifiers[key]))
+			}
+		}
+		if len(qualifiers) > 0 {
+			builder.WriteString("?")
+			builder.WriteString(strings.Join(qualifiers, "&"))
+		}
+	}
+	if subpath := strings.Trim(p.Subpath, "/"); subpath != "" {
+		builder.WriteString("#")
+		segments := []string{}
+		for _, segment := range strings.Split(subpath, "/") {
+			segments = append(segments, escape(segment))
+		}
+		builder.WriteString(strings.Join(segments, "/"))
+	}
+	return builder.String()
+}
+
+// Parse parses a package URL.
+// Parsing then formatting a package URL yields its canonical form, so that two package URLs can be compared as strings.
+func Parse(value string) (PackageURL, error) {
+	remainder, found := strings.CutPrefix(value, scheme)
+	if !found {
+		return PackageURL{}, fmt.Errorf("%w: %q does not start with %q", ErrInvalidPurl, value, scheme)
+	}
+
+	purl := PackageURL{}
+	var err error
+
+	remainder, subpath, _ := strings.Cut(remainder, "#")
+	if purl.Subpath, err = unescapeSegments(strings.Trim(subpath, "/")); err != nil {
+		return PackageURL{}, fmt.Errorf("%w: %q: %v", ErrInvalidPurl, value, err)
+	}
+
+	remainder, qualifiers, _ := strings.Cut(remainder, "?")
+	if qualifiers != "" {
+		purl.Qualifiers = map[string]string{}
+		for _, qualifier := range strings.Split(qualifiers, "&") {
+			key, qualifierValue, found := strings.Cut(qualifier, "=")
+			if !found || key == "" {
+				return PackageURL{}, fmt.Errorf("%w: %q: invalid qualifier %q", ErrInvalidPurl, value, qualifier)
+			}
+			if purl.Qualifiers[strings.ToLower(key)], err = unescape(qualifierValue); err != nil {
+				return PackageURL{}, fmt.Errorf("%w: %q: %v", ErrInvalidPurl, value, err)
+			}
+		}
+	}
+
+	// The scheme may be followed by slashes, which are not significant
+	remainder = strings.TrimLeft(remainder, "/")
+	// The version is separated from the name by the last "@", which cannot belong to an encoded namespace
+	if index := strings.LastIndex(remainder, "@"); index > strings.LastIndex(remainder, "/") {
+		if purl.Version, err = unescape(remainder[index+1:]); err != nil {
+			return PackageURL{}, fmt.Errorf("%w: %q: %v", ErrInvalidPurl, value, err)
+		}
+		remainder = remainder[:index]
+	}
+
+	segments := strings.Split(strings.Trim(remainder, "/"), "/")
+	if len(segments) < 2 || segments[0] == "" {
+		return PackageURL{}, fmt.Errorf("%w: %q has no type or no name", ErrInvalidPurl, value)
+	}
+	purl.Type = strings.ToLower(segments[0])
+	if purl.Name, err = unescape(segments[len(segments)-1]); err != nil {
+		return PackageURL{}, fmt.Errorf("%w: %q: %v", ErrInvalidPurl, value, err)
+	}
+	if purl.Name == "" {
+		return PackageURL{}, fmt.Errorf("%w: %q has no name", ErrInvalidPurl, value)
+	}
+	if purl.Namespace, err = unescapeSegments(strings.Join(segments[1:len(segments)-1], "/")); err != nil {
+		return PackageURL{}, fmt.Errorf("%w: %q: %v", ErrInvalidPurl, value, err)
+	}
+
+	return purl, nil
+}
+
+// PackageName returns the name of the package as used by its ecosystem, that is the namespace and the name joined by a "/".
+func (p PackageURL) PackageName() string {
+	if p.Namespace == "" {
+		return p.Name
+	}
+	return p.Namespace + "/" + p.Name
+}
+
+// escape percent-encodes every character of a component, except the unreserved characters of RFC 3986.
+func escape(component string) string {
+	builder := strings.Builder{}
+	for _, b := range []byte(component) {
+		if isUnreserved(b) {
+			builder.WriteByte(b)
+			continue
+		}
+		fmt.Fprintf(&builder, "%%%02X", b)
+	}
+	return builder.String()
+}
+
+// unescape decodes the percent-encoded characters of a component.
+func unescape(component string) (string, error) {
+	builder := strings.Builder{}
+	for i := 0; i < len(component); i++ {
+		if component[i] != '%' {
+			builder.WriteByte(component[i])
+			continue
+		}
+		if i+2 >= len(component) || !isHex(component[i+1]) || !isHex(component[i+2]) {
+			return "", fmt.Errorf("invalid percent-encoding in %q", component)
+		}
+		builder.WriteByte(fromHex(component[i+1])<<4 | fromHex(component[i+2]))
+		i += 2
+	}
+	return builder.String(), nil
+}
+
+// unescapeSegments decodes every segment of a "/" separated path, dropping the empty ones.
+func unescapeSegments(path string) (string, error) {
+	segments := []string{}
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		decoded, err := unescape(segment)
+		if err != nil {
+			return "", err
+		}
+		segments = append(segments, decoded)
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+func isUnreserved(b byte) bool {
+	return 'a' <= b && b <= 'z' || 'A' <= b && b <= 'Z' || '0' <= b && b <= '9' || b == '-' || b == '.' || b == '_' || b == '~'
+}
+
+func isHex(b byte) bool {
+	return '0' <= b && b <= '9' || 'a' <= b && b <= 'f' || 'A' <= b && b <= 'F'
+}
+
+func fromHex(b byte) byte {
+	switch {
+	case '0' <= b && b <= '9':
+		return b - '0'
+	case 'a' <= b && b <= 'f':
+		return b - 'a' + 10
+	default:
+		return b - 'A' + 10
+	}
+}
@@ -0,0 +1,238 @@
+package licenses
+
+import (
+	"context"
+	"strings"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/classification"
+	"github.com/CodeClarityCE/plugin-sca-license/src/ecosystem"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+	knowledge "github.com/CodeClarityCE/utility-types/knowledge_db"
+	"github.com/google/uuid"
+	"github.com/uptrace/bun"
+)
+
+// batchSize is the maximum number of values bound to a single IN (...) clause
+const batchSize = 1000
+
+// Dependency identifies a version of a package.
+type Dependency struct {
+	Name    string
+	Version string
+}
+
+// DeclaredLicense is the license declared by a dependency in the knowledge base.
+type DeclaredLicense struct {
+	// Declared is the license string as declared by the dependency, the entries of a license array being separated by commas
+	Declared string
+	// Entries are the licenses declared by the dependency, more than one when it declares a license array
+	Entries []string
+	// LicenseText is the text of the license file of the dependency, when the knowledge base has it
+	LicenseText string
+	// Fallback is set when no license is known for the exact version and the package-level license was used instead
+	Fallback bool
+	// Err is ErrPackageNotFound or ErrLicenseEmpty when the dependency has no declared license
+	Err error
+}
+
+// Licenses indexes SPDX licenses by their identifier.
+type Licenses map[string]knowledge.License
+
+// GetDeclaredLicenses retrieves the licenses declared by a set of dependencies of an ecosystem.
+// Only the packages of the ecosystem are considered, a package of another ecosystem with the same name is ignored.
+// Packages and versions are fetched in batches, so that the number of queries does not grow with every dependency.
+// The license declared for the exact version is used when the knowledge base has one,
+// otherwise the package-level license is used and DeclaredLicense.Fallback is set.
+// Licenses declared as arrays, such as the legacy npm licenses array, are returned entry by entry in DeclaredLicense.Entries.
+// A KnowledgeBaseError is returned if a query fails.
+func GetDeclaredLicenses(knowledge_db *bun.DB, dependencyEcosystem ecosystem.Ecosystem, dependencies []Dependency) (map[Dependency]DeclaredLicense, error) {
+	packages, err := getPackages(knowledge_db, dependencyEcosystem, dependencies)
+	if err != nil {
+		return nil, err
+	}
+	versionLicenses, err := getVersionLicenses(knowledge_db, dependencies, packages)
+	if err != nil {
+		return nil, err
+	}
+
+	declaredLicenses := map[Dependency]DeclaredLicense{}
+	for _, dependency := range dependencies {
+		pkg, found := packages[dependency.Name]
+		if !found {
+			declaredLicenses[dependency] = DeclaredLicense{Err: ErrPackageNotFound}
+			continue
+		}
+
+		declared, found := versionLicenses[dependency]
+		if !found {
+			declared = PackageLicense(pkg)
+		}
+		if len(declared.Entries) == 0 {
+			declaredLicenses[dependency] = DeclaredLicense{Fallback: !found, Err: ErrLicenseEmpty}
+			continue
+		}
+		declared.Declared = strings.Join(declared.Entries, ", ")
+		declared.Fallback = !found
+		declaredLicenses[dependency] = declared
+	}
+
+	return declaredLicenses, nil
+}
+
+// GetLicenses retrieves the SPDX licenses with the given identifiers, in batches.
+// Identifiers that do not exist in the knowledge base are missing from the result.
+// A KnowledgeBaseError is returned if a query fails.
+func GetLicenses(knowledge_db *bun.DB, licenseIds []string) (Licenses, error) {
+	licenses := Licenses{}
+	for _, batch := range batches(unique(licenseIds)) {
+		var found []knowledge.License
+		err := knowledge_db.NewSelect().Model(&found).Where("\"licenseId\" IN (?)", bun.In(batch)).Scan(context.Background())
+		if err != nil {
+			return nil, &KnowledgeBaseError{Operation: "retrieve licenses", Err: err}
+		}
+		for _, license := range found {
+			licenses[license.LicenseID] = license
+		}
+	}
+
+	return licenses, nil
+}
+
+// Resolve matches every license referenced by an SPDX license expression against the indexed licenses.
+// Identifiers that are not indexed are reported in DependencyLicenses.Unresolved,
+// except classification.LICENSE_PROPRIETARY which is not an SPDX license but is reported in DependencyLicenses.Proprietary.
+func (l Licenses) Resolve(expression spdx.Expression) DependencyLicenses {
+	resolved := DependencyLicenses{
+		Declared:   expression.String(),
+		Expression: expression,
+		Licenses:   []knowledge.License{},
+		Unresolved: []string{},
+	}
+
+	for _, licenseId := range expression.Licenses() {
+		if licenseId == classification.LICENSE_PROPRIETARY {
+			resolved.Proprietary = true
+			continue
+		}
+		license, found := l[licenseId]
+		if !found {
+			resolved.Unresolved = append(resolved.Unresolved, licenseId)
+			continue
+		}
+		resolved.Licenses = append(resolved.Licenses, license)
+	}
+
+	return resolved
+}
+
+// getPackages retrieves the packages of a set of dependencies of an ecosystem, indexed by name.
+func getPackages(knowledge_db *bun.DB, dependencyEcosystem ecosystem.Ecosystem, dependencies []Dependency) (map[string]knowledge.Package, error) {
+	names := []string{}
+	for _, dependency := range dependencies {
+		names = append(names, dependency.Name)
+	}
+
+	packages := map[string]knowledge.Package{}
+	for _, batch := range batches(unique(names)) {
+		var found []knowledge.Package
+		err := knowledge_db.NewSelect().Model(&found).
+			Where("name IN (?)", bun.In(batch)).
+			Where("language = ?", dependencyEcosystem.KnowledgeLanguage()).
+			Scan(context.Background())
+		if err != nil {
+			return nil, &KnowledgeBaseError{Operation: "retrieve " + string(dependencyEcosystem) + " packages", Err: err}
+		}
+		for _, pkg := range found {
+			if _, exists := packages[pkg.Name]; !exists {
+				packages[pkg.Name] = pkg
+			}
+		}
+	}
+
+	return packages, nil
+}
+
+// packageVersion identifies a version of a package of the knowledge base.
+type packageVersion struct {
+	packageId uuid.UUID
+	version   string
+}
+
+// getVersionLicenses retrieves the licenses declared by the exact versions of a set of dependencies.
+// Versions that are unknown or do not declare a license are missing from the result.
+func getVersionLicenses(knowledge_db *bun.DB, dependencies []Dependency, packages map[string]knowledge.Package) (map[Dependency]DeclaredLicense, error) {
+	wanted := map[uuid.UUID]map[string]Dependency{}
+	pairs := []packageVersion{}
+	for _, dependency := range dependencies {
+		pkg, found := packages[dependency.Name]
+		if !found {
+			continue
+		}
+		if _, exists := wanted[pkg.Id]; !exists {
+			wanted[pkg.Id] = map[string]Dependency{}
+		}
+		if _, exists := wanted[pkg.Id][dependency.Version]; !exists {
+			pairs = append(pairs, packageVersion{packageId: pkg.Id, version: dependency.Version})
+		}
+		wanted[pkg.Id][dependency.Version] = dependency
+	}
+
+	licenses := map[Dependency]DeclaredLicense{}
+	// Pairs are batched together, so that neither the package nor the version list of a query exceeds batchSize values
+	for _, batch := range batches(pairs) {
+		packageIds := []uuid.UUID{}
+		seen := map[uuid.UUID]bool{}
+		versionNames := []string{}
+		for _, pair := range batch {
+			if !seen[pair.packageId] {
+				seen[pair.packageId] = true
+				packageIds = append(packageIds, pair.packageId)
+			}
+			versionNames = append(versionNames, pair.version)
+		}
+
+		// The filters are only there to narrow the query down, exact pairs are matched below
+		var found []knowledge.Version
+		err := knowledge_db.NewSelect().Model(&found).
+			Where("package_id IN (?)", bun.In(packageIds)).
+			Where("version IN (?)", bun.In(unique(versionNames))).
+			Scan(context.Background())
+		if err != nil {
+			return nil, &KnowledgeBaseError{Operation: "retrieve versions", Err: err}
+		}
+
+		for _, version := range found {
+			dependency, found := wanted[version.PackageID][version.Version]
+			if !found {
+				continue
+			}
+			if license := VersionLicense(version); len(license.Entries) > 0 {
+				licenses[dependency] = license
+			}
+		}
+	}
+
+	return licenses, nil
+}
+
+// batches splits values into slices of at most batchSize values.
+func batches[T any](values []T) [][]T {
+	result := [][]T{}
+	for start := 0; start < len(values); start += batchSize {
+		result = append(result, values[start:min(start+batchSize, len(values))])
+	}
+	return result
+}
+
+// unique removes the duplicates of a list of strings, keeping their first occurrence.
+func unique(values []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+	return result
+}
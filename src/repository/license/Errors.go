@@ -0,0 +1,35 @@
+package licenses
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrPackageNotFound is returned when a package does not exist in the knowledge base
+	ErrPackageNotFound = errors.New("package not found in the knowledge base")
+	// ErrLicenseEmpty is returned when a package does not declare any license
+	ErrLicenseEmpty = errors.New("package does not declare a license")
+)
+
+// KnowledgeBaseError is returned when the knowledge base could not be queried.
+// Unlike ErrPackageNotFound or ErrLicenseEmpty, it says nothing about the license of a package:
+// the lookup failed and may succeed once the knowledge base is reachable again.
+type KnowledgeBaseError struct {
+	Operation string
+	Err       error
+}
+
+func (e *KnowledgeBaseError) Error() string {
+	return fmt.Sprintf("knowledge base query failed: %s: %v", e.Operation, e.Err)
+}
+
+func (e *KnowledgeBaseError) Unwrap() error {
+	return e.Err
+}
+
+// IsKnowledgeBaseError reports whether an error was caused by a failed knowledge base query.
+func IsKnowledgeBaseError(err error) bool {
+	var knowledgeBaseError *KnowledgeBaseError
+	return errors.As(err, &knowledgeBaseError)
+}
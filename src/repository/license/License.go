@@ -2,11 +2,70 @@ package licenses
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
 	knowledge "github.com/CodeClarityCE/utility-types/knowledge_db"
 	"github.com/uptrace/bun"
 )
 
+// LICENSE_TEXT_FIELD is the field of the metadata (the extra column) of packages and versions of the knowledge base
+// that holds the text of the license file of the package, e.g. the LICENSE file of an npm tarball.
+// Packages without it that declare "SEE LICENSE IN <file>" are reported unresolved.
+const LICENSE_TEXT_FIELD = "licenseText"
+
+// DependencyLicenses holds the license information resolved for a single dependency.
+type DependencyLicenses struct {
+	// Declared is the license string as declared by the dependency
+	Declared string
+	// Expression is the parsed license expression declared by the dependency
+	Expression spdx.Expression
+	// Licenses are the licenses of the expression that are known SPDX licenses
+	Licenses []knowledge.License
+	// Unresolved are the identifiers of the expression that are not known SPDX licenses
+	Unresolved []string
+	// Proprietary is set when the expression declares the dependency as proprietary (classification.LICENSE_PROPRIETARY),
+	// which is neither an SPDX license nor an unresolved identifier
+	Proprietary bool
+	// Fallback is set when no license is known for the exact version and the package-level license was used instead
+	Fallback bool
+}
+
+// CheckKnowledgeBase verifies that the knowledge base can be reached.
+// It returns a KnowledgeBaseError otherwise.
+func CheckKnowledgeBase(knowledge_db *bun.DB) error {
+	err := knowledge_db.PingContext(context.Background())
+	if err != nil {
+		return &KnowledgeBaseError{Operation: "ping", Err: err}
+	}
+
+	return nil
+}
+
+// GetKnowledgeBaseFingerprint returns a value that changes whenever the packages, versions or licenses of the knowledge base are written to,
+// for instance when the knowledge base is re-imported.
+// It is derived from the rows of these tables: their number and the latest transaction that wrote one of them (its xmin),
+// so that it does not depend on statistics that PostgreSQL updates asynchronously and may reset.
+func GetKnowledgeBaseFingerprint(knowledge_db *bun.DB) (string, error) {
+	parts := []string{}
+	for _, model := range []any{(*knowledge.Package)(nil), (*knowledge.Version)(nil), (*knowledge.License)(nil)} {
+		var rows, lastWrite int64
+		err := knowledge_db.NewSelect().
+			Model(model).
+			ColumnExpr("count(*)").
+			ColumnExpr("COALESCE(max(xmin::text::bigint), 0)").
+			Scan(context.Background(), &rows, &lastWrite)
+		if err != nil {
+			return "", &KnowledgeBaseError{Operation: "fingerprint", Err: err}
+		}
+		parts = append(parts, fmt.Sprintf("%s:%d:%d", knowledge_db.Table(reflect.TypeOf(model)).Name, rows, lastWrite))
+	}
+
+	return strings.Join(parts, ","), nil
+}
+
 // GetSPDXLicenseByName retrieves an SPDX license by its name from the database.
 // It takes the name of the license as a parameter and returns a pointer to the license and an error, if any.
 func GetSPDXLicenseByName(name string, knowledge_db *bun.DB) (knowledge.License, error) {
@@ -19,24 +78,60 @@ func GetSPDXLicenseByName(name string, knowledge_db *bun.DB) (knowledge.License,
 	return license, nil
 }
 
-// GetDependencyLicenses retrieves the licenses associated with a specific dependency.
-// It takes the dependency name and version as input parameters and returns a slice of licenseTypes.License and an error.
-// The function queries the database to find the licenses associated with the given dependency and constructs a list of licenses.
-// If the query construction or execution fails, an error is returned.
-// The licenses are returned as a slice of licenseTypes.License.
-func GetDependencyLicenses(knowledge_db *bun.DB, depName string, depVersion string) ([]knowledge.License, error) {
-	var dependency knowledge.Package
-
-	err := knowledge_db.NewSelect().Model(&dependency).Where("name = ?", depName).Scan(context.Background(), &dependency)
+// GetSPDXLicenses retrieves every SPDX license stored in the database, including its text and template.
+func GetSPDXLicenses(knowledge_db *bun.DB) ([]knowledge.License, error) {
+	var licenses []knowledge.License
+	err := knowledge_db.NewSelect().Model(&licenses).Scan(context.Background())
 	if err != nil {
-		return nil, err
+		return nil, &KnowledgeBaseError{Operation: "retrieve licenses", Err: err}
 	}
 
-	var license knowledge.License
-	err = knowledge_db.NewSelect().Model(&license).Where("\"licenseId\" = ?", dependency.License).Scan(context.Background())
-	if err != nil {
-		return nil, err
+	return licenses, nil
+}
+
+// VersionLicense extracts the license of a version from its metadata.
+// Registries store it either as a plain string, as an object with a "type" field or as an array of either,
+// such as the composer license array. Versions without a license field may declare the legacy npm licenses array instead.
+func VersionLicense(version knowledge.Version) DeclaredLicense {
+	entries := licenseEntries(version.Extra["license"])
+	if len(entries) == 0 {
+		entries = licenseEntries(version.Extra["licenses"])
+	}
+	return DeclaredLicense{Entries: entries, LicenseText: licenseText(version.Extra)}
+}
+
+// PackageLicense extracts the package-level license of a package, which is used when its version does not declare any.
+func PackageLicense(pkg knowledge.Package) DeclaredLicense {
+	declared := DeclaredLicense{Entries: licenseEntries(pkg.License), LicenseText: licenseText(pkg.Extra)}
+	if len(declared.Entries) == 0 {
+		for _, license := range pkg.Licenses {
+			declared.Entries = append(declared.Entries, licenseEntries(license.Type)...)
+		}
+	}
+	return declared
+}
+
+// licenseEntries returns the non empty licenses of a license field, which is a string, an object with a "type" field or an array of either.
+func licenseEntries(value any) []string {
+	entries := []string{}
+	switch license := value.(type) {
+	case string:
+		if strings.TrimSpace(license) != "" {
+			entries = append(entries, strings.TrimSpace(license))
+		}
+	case map[string]any:
+		entries = append(entries, licenseEntries(license["type"])...)
+	case []any:
+		for _, entry := range license {
+			entries = append(entries, licenseEntries(entry)...)
+		}
 	}
+	return entries
+}
 
-	return []knowledge.License{license}, nil
+// licenseText returns the text of the license file stored along with the metadata of a package or version, if any.
+// It is the text a declared license such as "SEE LICENSE IN LICENSE.md" refers to.
+func licenseText(extra map[string]any) string {
+	text, _ := extra[LICENSE_TEXT_FIELD].(string)
+	return text
 }
@@ -1,57 +1,128 @@
 package license
 
 import (
+	"fmt"
+	"log"
+	"slices"
 	"time"
 
 	sbom "github.com/CodeClarityCE/plugin-sbom-javascript/src/types/sbom/js"
+	"github.com/CodeClarityCE/plugin-sca-license/src/classification"
+	"github.com/CodeClarityCE/plugin-sca-license/src/config"
+	"github.com/CodeClarityCE/plugin-sca-license/src/ecosystem"
+	exceptionManager "github.com/CodeClarityCE/plugin-sca-license/src/exceptionManager"
+	"github.com/CodeClarityCE/plugin-sca-license/src/licenseCache"
 	licenseMatcherManager "github.com/CodeClarityCE/plugin-sca-license/src/licenseMatcher"
 	outputGenerator "github.com/CodeClarityCE/plugin-sca-license/src/outputGenerator"
+	licenseRepository "github.com/CodeClarityCE/plugin-sca-license/src/repository/license"
 	types "github.com/CodeClarityCE/plugin-sca-license/src/types"
+	"github.com/CodeClarityCE/plugin-sca-license/src/workerPool"
 	codeclarity "github.com/CodeClarityCE/utility-types/codeclarity_db"
 	"github.com/CodeClarityCE/utility-types/exceptions"
-	exceptionManager "github.com/CodeClarityCE/utility-types/exceptions"
-	knowledge "github.com/CodeClarityCE/utility-types/knowledge_db"
 	"github.com/uptrace/bun"
 )
 
+// licenseDataSources defines, for every supported language, where the license data is retrieved from.
+// Composer lock files carry the license of every package, so PHP does not depend on the knowledge base coverage.
+var licenseDataSources = map[string]licenseMatcherManager.LicenseDataSource{
+	"JS":  licenseMatcherManager.LICENSE_DATA_SOURCE_DB,
+	"PHP": licenseMatcherManager.LICENSE_DATA_SOURCE_SBOM,
+}
+
 // Start is a function that starts the analysis process for a given SBOM (Software Bill of Materials).
-// It takes the SBOM ID, language ID, license policy, and database as input parameters.
+// It takes the database, SBOM, language ID, analysis configuration and license cache as input parameters.
+// Workspaces are analyzed concurrently, up to config.Concurrency at once.
+// The cache is shared across analyses and emptied when the knowledge base changed, a nil cache disables caching.
+// The errors of the analysis are collected for this SBOM only and reported in its output.
 // It returns the analysis output as a types.Output struct.
-func Start(knowledge_db *bun.DB, sbom sbom.Output, languageId string, licensePolicy knowledge.LicensePolicy, start time.Time) types.Output {
+func Start(knowledge_db *bun.DB, sbom sbom.Output, languageId string, config config.Config, cache *licenseCache.LicenseCache, start time.Time) types.Output {
+	analysisErrors := exceptionManager.NewErrors()
 
 	// Check if the previous stage finished correctly
 	if sbom.AnalysisInfo.Status != codeclarity.SUCCESS {
-		exceptionManager.AddError(
+		analysisErrors.AddError(
 			"Execution of the previous stage was unsuccessful, upon which the current stage relies", exceptions.PREVIOUS_STAGE_FAILED,
 			"Execution of the previous stage was unsuccessful, upon which the current stage relies", exceptions.PREVIOUS_STAGE_FAILED,
 		)
 
-		return outputGenerator.FailureOutput(sbom.AnalysisInfo, start)
+		return outputGenerator.FailureOutput(sbom.AnalysisInfo, analysisErrors, start)
 	}
 
 	var licenseMatcher licenseMatcherManager.LicenseMatcher
 
 	language_supported := false
 	// Check which language was requested
-	if languageId == "JS" || languageId == "PHP" {
+	licenseDataSource, sourceFound := licenseDataSources[languageId]
+	dependencyEcosystem, ecosystemFound := ecosystem.FromLanguage(languageId)
+	if sourceFound && ecosystemFound {
 		licenseMatcher = licenseMatcherManager.LicenseMatcher{
-			LicenseDataSource:   licenseMatcherManager.LICENSE_DATA_SOURCE_DB,
+			LicenseDataSource:   licenseDataSource,
 			PostProcessLicenses: true,
+			Ecosystem:           dependencyEcosystem,
+			Cache:               cache,
+			CacheRecorder:       &licenseCache.Recorder{},
+			EvaluatedAt:         start,
+			ProjectLicense:      config.ProjectLicense,
 		}
+		licenseMatcher.VersionSeperator, licenseMatcher.ImportPathSeperator = outputGenerator.Seperators(sbom.AnalysisInfo)
 		language_supported = true
 	}
 
 	// In case language is not supported return an error
 	if !language_supported {
-		exceptionManager.AddError("", exceptions.UNSUPPORTED_LANGUAGE_REQUESTED, "", exceptions.UNSUPPORTED_LANGUAGE_REQUESTED)
-		return outputGenerator.FailureOutput(sbom.AnalysisInfo, start)
+		analysisErrors.AddError("", exceptions.UNSUPPORTED_LANGUAGE_REQUESTED, "", exceptions.UNSUPPORTED_LANGUAGE_REQUESTED)
+		return outputGenerator.FailureOutput(sbom.AnalysisInfo, analysisErrors, start)
 	}
 
-	workSpaceData := map[string]types.WorkSpaceLicenseInfoInternal{}
+	// Without the knowledge base no license can be resolved, fail instead of reporting every license as unknown
+	if err := licenseRepository.CheckKnowledgeBase(knowledge_db); err != nil {
+		analysisErrors.AddError(
+			"The license knowledge base is unreachable", exceptions.GENERIC_ERROR,
+			err.Error(), exceptions.GENERIC_ERROR,
+		)
+		return outputGenerator.FailureOutput(sbom.AnalysisInfo, analysisErrors, start)
+	}
+
+	// Cached licenses must not outlive a re-import of the knowledge base
+	if cache != nil {
+		fingerprint, err := licenseRepository.GetKnowledgeBaseFingerprint(knowledge_db)
+		if err != nil {
+			log.Printf("Unable to fingerprint the knowledge base, emptying the license cache: %v", err)
+			cache.Purge()
+		} else if cache.Refresh(fingerprint) {
+			log.Printf("The knowledge base changed, the license cache was emptied")
+		}
+	}
+
+	// Post processing maps the licenses that are not SPDX licenses onto the SPDX licenses of the knowledge base
+	if licenseMatcher.PostProcessLicenses {
+		spdxLicenses, err := licenseRepository.GetSPDXLicenses(knowledge_db)
+		if err != nil {
+			log.Printf("Unable to retrieve SPDX licenses, licenses will not be post processed: %v", err)
+		} else {
+			licenseMatcher.LoadSPDXLicenses(spdxLicenses)
+			classification.LoadSPDXLicenses(spdxLicenses)
+		}
+	}
+
+	workspaceKeys := []string{}
+	for workspaceKey := range sbom.WorkSpaces {
+		workspaceKeys = append(workspaceKeys, workspaceKey)
+	}
+	slices.Sort(workspaceKeys)
+
+	licensePolicy := config.ScopedPolicy()
+
+	// Every worker writes the result of its workspace at the index of the workspace
+	results := make([]types.WorkSpaceLicenseInfoInternal, len(workspaceKeys))
+	workerPool.Run(len(workspaceKeys), config.Concurrency, func(index int) {
+		workspace := sbom.WorkSpaces[workspaceKeys[index]]
+		results[index] = licenseMatcher.GetWorkSpaceLicenses(knowledge_db, workspaceKeys[index], workspace, licensePolicy)
+	})
 
-	// workSpaceData := map[string]types.WorkSpaceVulnerabilitiesInternal{}
-	for workspaceKey, workspace := range sbom.WorkSpaces {
-		workSpaceData[workspaceKey] = licenseMatcher.GetWorkSpaceLicenses(knowledge_db, workspace.Dependencies, licensePolicy)
+	workSpaceData := map[string]types.WorkSpaceLicenseInfoInternal{}
+	for index, workspaceKey := range workspaceKeys {
+		workSpaceData[workspaceKey] = results[index]
 	}
 
 	// Generate truncated workspace data for the output
@@ -61,7 +132,12 @@ func Start(knowledge_db *bun.DB, sbom sbom.Output, languageId string, licensePol
 		workSpaceLicenseInfo := types.WorkSpaceLicenseInfo{
 			LicensesDepMap:              map[string][]string{},
 			NonSpdxLicensesDepMap:       map[string][]string{},
+			ProprietaryDependencies:     workSpaceLicenseInfoInternal.ProprietaryDependencies,
 			LicenseComplianceViolations: []string{},
+			PolicyViolations:            workSpaceLicenseInfoInternal.PolicyViolations,
+			LicenseIncompatibilities:    workSpaceLicenseInfoInternal.LicenseIncompatibilities,
+			ProjectLicense:              workSpaceLicenseInfoInternal.ProjectLicense,
+			ProjectLicenseSource:        workSpaceLicenseInfoInternal.ProjectLicenseSource,
 			DependencyInfo:              workSpaceLicenseInfoInternal.DependencyInfo,
 		}
 
@@ -76,6 +152,7 @@ func Start(knowledge_db *bun.DB, sbom sbom.Output, languageId string, licensePol
 		for licenseKey := range workSpaceLicenseInfoInternal.LicenseComplianceViolations {
 			workSpaceLicenseInfo.LicenseComplianceViolations = append(workSpaceLicenseInfo.LicenseComplianceViolations, licenseKey)
 		}
+		slices.Sort(workSpaceLicenseInfo.LicenseComplianceViolations)
 
 		workSpaceDataTruncated[workSpaceKey] = workSpaceLicenseInfo
 	}
@@ -83,6 +160,20 @@ func Start(knowledge_db *bun.DB, sbom sbom.Output, languageId string, licensePol
 	// Generate license stats
 	analysisStats := outputGenerator.GenerateAnalysisStats(workSpaceData)
 
+	// The analysis is degraded if the knowledge base failed to answer some of the lookups
+	if analysisStats.NumberOfLookupErrors > 0 {
+		message := fmt.Sprintf("The licenses of %d dependencies could not be looked up in the knowledge base", analysisStats.NumberOfLookupErrors)
+		analysisErrors.AddError(message, exceptions.GENERIC_ERROR, message, exceptions.GENERIC_ERROR)
+	}
+
+	// The SBOM does not declare the license of the project, without a configured one compatibility is not checked
+	if config.ProjectLicense.IsEmpty() {
+		message := "The project license is not configured, the compatibility of dependency licenses with it was not checked"
+		analysisErrors.AddError(message, exceptions.GENERIC_ERROR, message, exceptions.GENERIC_ERROR)
+	}
+
 	// Return the analysis results
-	return outputGenerator.SuccessOutput(workSpaceDataTruncated, analysisStats, sbom.AnalysisInfo, start)
+	output := outputGenerator.SuccessOutput(workSpaceDataTruncated, analysisStats, sbom.AnalysisInfo, analysisErrors, start)
+	output.AnalysisInfo.CacheStats = outputGenerator.GenerateCacheStats(licenseMatcher.CacheRecorder, cache)
+	return output
 }
@@ -0,0 +1,303 @@
+package semver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidRange is returned when a string is not a version range.
+var ErrInvalidRange = errors.New("invalid version range")
+
+// Range is a set of versions, written with the range syntax of npm (e.g. "^1.2.0", ">=1.0.0 <2.0.0 || 3.x").
+type Range struct {
+	raw string
+	// sets are alternatives, a version is in the range if it satisfies every comparator of one of the sets
+	sets [][]comparator
+}
+
+// comparator bounds versions with an operator, e.g. ">=1.2.0".
+type comparator struct {
+	operator string
+	version  Version
+	// implicit is set on the bounds introduced by desugaring (e.g. the "<2.0.0-0" of "^1.2.0"),
+	// which must not allow prereleases on their own
+	implicit bool
+}
+
+// ParseRange parses a version range.
+// It supports comparators (=, <, <=, >, >=), caret and tilde ranges, x-ranges (e.g. "1.x" or "*"),
+// hyphen ranges (e.g. "1.0.0 - 2.0.0"), and their combination with spaces (and) and "||" (or).
+func ParseRange(value string) (Range, error) {
+	parsed := Range{raw: strings.TrimSpace(value)}
+
+	for _, alternative := range strings.Split(value, "||") {
+		set, err := parseSet(strings.TrimSpace(alternative))
+		if err != nil {
+			return Range{}, fmt.Errorf("%w: %q: %v", ErrInvalidRange, value, err)
+		}
+		parsed.sets = append(parsed.sets, set)
+	}
+
+	return parsed, nil
+}
+
+// Contains reports whether a version is in the range.
+// As with npm, a prerelease is only in the range if a comparator of the matching set is a prerelease of the same major, minor and patch.
+func (r Range) Contains(version Version) bool {
+	for _, set := range r.sets {
+		if satisfies(set, version) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the range as it was written.
+func (r Range) String() string {
+	return r.raw
+}
+
+func satisfies(set []comparator, version Version) bool {
+	for _, bound := range set {
+		if !bound.matches(version) {
+			return false
+		}
+	}
+	if len(version.Prerelease) == 0 {
+		return true
+	}
+
+	for _, bound := range set {
+		if !bound.implicit && len(bound.version.Prerelease) > 0 &&
+			bound.version.Major == version.Major && bound.version.Minor == version.Minor && bound.version.Patch == version.Patch {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparator) matches(version Version) bool {
+	result := version.Compare(c.version)
+	switch c.operator {
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	default:
+		return result == 0
+	}
+}
+
+// parseSet parses the comparators of an alternative of a range.
+func parseSet(value string) ([]comparator, error) {
+	if from, to, found := strings.Cut(value, " - "); found {
+		return hyphenRange(strings.TrimSpace(from), strings.TrimSpace(to))
+	}
+
+	// An operator may be separated from its version by spaces (e.g. ">= 1.2.0")
+	tokens := []string{}
+	pending := ""
+	for _, field := range strings.Fields(value) {
+		if strings.Trim(field, "<>=~^") == "" {
+			pending += field
+			continue
+		}
+		tokens = append(tokens, pending+field)
+		pending = ""
+	}
+	if pending != "" {
+		return nil, fmt.Errorf("operator %q has no version", pending)
+	}
+	if len(tokens) == 0 {
+		tokens = append(tokens, "*")
+	}
+
+	set := []comparator{}
+	for _, token := range tokens {
+		comparators, err := desugar(token)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, comparators...)
+	}
+	return set, nil
+}
+
+// desugar turns a single token of a range (e.g. "^1.2", ">=1.0.0" or "1.x") into the comparators it stands for.
+func desugar(token string) ([]comparator, error) {
+	operator := ""
+	for _, candidate := range []string{">=", "<=", "~>", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(token, candidate) {
+			operator = candidate
+			break
+		}
+	}
+	partial, err := parsePartial(strings.TrimPrefix(token, operator))
+	if err != nil {
+		return nil, err
+	}
+	lower, upper := partial.bounds()
+
+	switch operator {
+	case "^":
+		return caret(partial), nil
+	case "~", "~>":
+		if partial.parts <= 1 {
+			return partial.span(lower, upper), nil
+		}
+		return []comparator{{operator: ">=", version: lower}, {operator: "<", version: Version{Major: lower.Major, Minor: lower.Minor + 1, Prerelease: []string{"0"}}, implicit: true}}, nil
+	case ">":
+		if partial.parts == 0 {
+			// Nothing is greater than every version
+			return []comparator{{operator: "<", version: Version{Prerelease: []string{"0"}}, implicit: true}}, nil
+		}
+		if partial.parts < 3 {
+			return []comparator{{operator: ">=", version: upper}}, nil
+		}
+		return []comparator{{operator: ">", version: lower}}, nil
+	case ">=":
+		return []comparator{{operator: ">=", version: lower}}, nil
+	case "<":
+		if partial.parts < 3 {
+			return []comparator{{operator: "<", version: withPrerelease(lower), implicit: true}}, nil
+		}
+		return []comparator{{operator: "<", version: lower}}, nil
+	case "<=":
+		if partial.parts == 0 {
+			return []comparator{{operator: ">=", version: Version{}}}, nil
+		}
+		if partial.parts < 3 {
+			return []comparator{{operator: "<", version: withPrerelease(upper), implicit: true}}, nil
+		}
+		return []comparator{{operator: "<=", version: lower}}, nil
+	default:
+		if partial.parts < 3 {
+			return partial.span(lower, upper), nil
+		}
+		return []comparator{{operator: "=", version: lower}}, nil
+	}
+}
+
+// hyphenRange turns "from - to" into an inclusive range, a partial upper bound includes every version it stands for.
+func hyphenRange(from string, to string) ([]comparator, error) {
+	lower, err := parsePartial(from)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := parsePartial(to)
+	if err != nil {
+		return nil, err
+	}
+
+	set := []comparator{}
+	lowerBound, _ := lower.bounds()
+	set = append(set, comparator{operator: ">=", version: lowerBound})
+	upperLower, upperUpper := upper.bounds()
+	switch {
+	case upper.parts == 0:
+	case upper.parts < 3:
+		set = append(set, comparator{operator: "<", version: withPrerelease(upperUpper), implicit: true})
+	default:
+		set = append(set, comparator{operator: "<=", version: upperLower})
+	}
+	return set, nil
+}
+
+// caret allows the changes that do not modify the left-most non-zero part of a version.
+func caret(partial partialVersion) []comparator {
+	lower, upper := partial.bounds()
+	if partial.parts == 0 {
+		return partial.span(lower, upper)
+	}
+
+	switch {
+	case lower.Major > 0 || partial.parts == 1:
+		upper = Version{Major: lower.Major + 1}
+	case lower.Minor > 0 || partial.parts == 2:
+		upper = Version{Minor: lower.Minor + 1}
+	default:
+		upper = Version{Minor: lower.Minor, Patch: lower.Patch + 1}
+	}
+	return []comparator{{operator: ">=", version: lower}, {operator: "<", version: withPrerelease(upper), implicit: true}}
+}
+
+// partialVersion is a version of which only the first parts may be known, e.g. "1.2" or "1.x".
+type partialVersion struct {
+	version Version
+	// parts is the number of known parts, from 0 (e.g. "*") to 3
+	parts int
+}
+
+func parsePartial(value string) (partialVersion, error) {
+	value = strings.TrimLeft(value, "v=")
+	if value == "" || value == "*" || value == "x" || value == "X" {
+		return partialVersion{}, nil
+	}
+
+	core, _, _ := strings.Cut(value, "+")
+	core, _, hasPrerelease := strings.Cut(core, "-")
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return partialVersion{}, fmt.Errorf("%q has too many parts", value)
+	}
+
+	partial := partialVersion{}
+	numbers := []*int{&partial.version.Major, &partial.version.Minor, &partial.version.Patch}
+	for index, part := range parts {
+		if part == "*" || part == "x" || part == "X" {
+			break
+		}
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return partialVersion{}, fmt.Errorf("%q has an invalid number %q", value, part)
+		}
+		*numbers[index] = number
+		partial.parts++
+	}
+
+	if partial.parts == 3 {
+		version, err := Parse(value)
+		if err != nil {
+			return partialVersion{}, err
+		}
+		partial.version = version
+	} else if hasPrerelease {
+		return partialVersion{}, fmt.Errorf("%q has a prerelease but no patch number", value)
+	}
+	return partial, nil
+}
+
+// bounds returns the lowest version the partial version stands for, and the version right after the highest one.
+func (p partialVersion) bounds() (Version, Version) {
+	lower := p.version
+	switch p.parts {
+	case 0:
+		return Version{}, Version{}
+	case 1:
+		return lower, Version{Major: lower.Major + 1}
+	case 2:
+		return lower, Version{Major: lower.Major, Minor: lower.Minor + 1}
+	default:
+		return lower, lower
+	}
+}
+
+// span returns the comparators of every version the partial version stands for.
+func (p partialVersion) span(lower Version, upper Version) []comparator {
+	if p.parts == 0 {
+		return []comparator{{operator: ">=", version: Version{}}}
+	}
+	return []comparator{{operator: ">=", version: lower}, {operator: "<", version: withPrerelease(upper), implicit: true}}
+}
+
+// withPrerelease returns the lowest prerelease of a version, so that an exclusive upper bound also excludes the prereleases of the bound.
+func withPrerelease(version Version) Version {
+	version.Prerelease = []string{"0"}
+	return version
+}
@@ -0,0 +1,110 @@
+package semver
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidVersion is returned when a string is not a semantic version.
+var ErrInvalidVersion = errors.New("invalid semantic version")
+
+// Version is a semantic version, as described in https://semver.org.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease []string
+	Build      string
+}
+
+// Parse parses a semantic version such as "1.2.3", "1.2.3-beta.1" or "1.2.3+build.5".
+// A leading "v" or "=" is ignored, as npm does.
+func Parse(value string) (Version, error) {
+	trimmed := strings.TrimLeft(strings.TrimSpace(value), "v=")
+
+	version := Version{}
+	trimmed, version.Build, _ = strings.Cut(trimmed, "+")
+	core, prerelease, hasPrerelease := strings.Cut(trimmed, "-")
+	if hasPrerelease {
+		if prerelease == "" {
+			return Version{}, fmt.Errorf("%w: %q has an empty prerelease", ErrInvalidVersion, value)
+		}
+		version.Prerelease = strings.Split(prerelease, ".")
+		for _, identifier := range version.Prerelease {
+			if identifier == "" {
+				return Version{}, fmt.Errorf("%w: %q has an empty prerelease identifier", ErrInvalidVersion, value)
+			}
+		}
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return Version{}, fmt.Errorf("%w: %q does not have a major, minor and patch number", ErrInvalidVersion, value)
+	}
+	numbers := []*int{&version.Major, &version.Minor, &version.Patch}
+	for index, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return Version{}, fmt.Errorf("%w: %q has an invalid number %q", ErrInvalidVersion, value, part)
+		}
+		*numbers[index] = number
+	}
+
+	return version, nil
+}
+
+// Compare returns -1, 0 or 1 depending on whether v is lower than, equal to or greater than other.
+// Build metadata does not take part in the comparison, and a prerelease is lower than its release.
+func (v Version) Compare(other Version) int {
+	if result := cmp.Or(cmp.Compare(v.Major, other.Major), cmp.Compare(v.Minor, other.Minor), cmp.Compare(v.Patch, other.Patch)); result != 0 {
+		return result
+	}
+
+	switch {
+	case len(v.Prerelease) == 0 && len(other.Prerelease) == 0:
+		return 0
+	case len(v.Prerelease) == 0:
+		return 1
+	case len(other.Prerelease) == 0:
+		return -1
+	}
+
+	for index := 0; index < min(len(v.Prerelease), len(other.Prerelease)); index++ {
+		if result := compareIdentifiers(v.Prerelease[index], other.Prerelease[index]); result != 0 {
+			return result
+		}
+	}
+	return cmp.Compare(len(v.Prerelease), len(other.Prerelease))
+}
+
+// String formats the version back into its semantic version form.
+func (v Version) String() string {
+	formatted := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.Prerelease) > 0 {
+		formatted += "-" + strings.Join(v.Prerelease, ".")
+	}
+	if v.Build != "" {
+		formatted += "+" + v.Build
+	}
+	return formatted
+}
+
+// compareIdentifiers compares two prerelease identifiers.
+// Numeric identifiers are compared numerically and are lower than alphanumeric identifiers, which are compared lexically.
+func compareIdentifiers(a string, b string) int {
+	numberA, errA := strconv.Atoi(a)
+	numberB, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(numberA, numberB)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
@@ -0,0 +1,80 @@
+package spdx
+
+import "strings"
+
+// DEPRECATED_LICENSES_VERSION is the version of the SPDX license list the table of deprecated identifiers below was taken from.
+// It says nothing about the SPDX licenses of the knowledge base, which are imported separately.
+const DEPRECATED_LICENSES_VERSION = "3.27"
+
+// deprecatedLicenses maps the deprecated identifiers of the SPDX license list, in lower case and with their "+" operator,
+// onto the licenses that replace them in the current list. Identifiers that bundled an exception are replaced by
+// the license along with the exception (e.g. "eCos-2.0" by "GPL-2.0-or-later WITH eCos-exception-2.0").
+var deprecatedLicenses = map[string]Expression{
+	"agpl-1.0":                         {License: "AGPL-1.0-only"},
+	"agpl-3.0":                         {License: "AGPL-3.0-only"},
+	"gfdl-1.1":                         {License: "GFDL-1.1-only"},
+	"gfdl-1.2":                         {License: "GFDL-1.2-only"},
+	"gfdl-1.3":                         {License: "GFDL-1.3-only"},
+	"gpl-1.0":                          {License: "GPL-1.0-only"},
+	"gpl-1.0+":                         {License: "GPL-1.0-or-later"},
+	"gpl-2.0":                          {License: "GPL-2.0-only"},
+	"gpl-2.0+":                         {License: "GPL-2.0-or-later"},
+	"gpl-3.0":                          {License: "GPL-3.0-only"},
+	"gpl-3.0+":                         {License: "GPL-3.0-or-later"},
+	"lgpl-2.0":                         {License: "LGPL-2.0-only"},
+	"lgpl-2.0+":                        {License: "LGPL-2.0-or-later"},
+	"lgpl-2.1":                         {License: "LGPL-2.1-only"},
+	"lgpl-2.1+":                        {License: "LGPL-2.1-or-later"},
+	"lgpl-3.0":                         {License: "LGPL-3.0-only"},
+	"lgpl-3.0+":                        {License: "LGPL-3.0-or-later"},
+	"gpl-2.0-with-autoconf-exception":  {License: "GPL-2.0-only", Exception: "Autoconf-exception-2.0"},
+	"gpl-2.0-with-bison-exception":     {License: "GPL-2.0-or-later", Exception: "Bison-exception-2.2"},
+	"gpl-2.0-with-classpath-exception": {License: "GPL-2.0-only", Exception: "Classpath-exception-2.0"},
+	"gpl-2.0-with-font-exception":      {License: "GPL-2.0-only", Exception: "Font-exception-2.0"},
+	"gpl-2.0-with-gcc-exception":       {License: "GPL-2.0-only", Exception: "GCC-exception-2.0"},
+	"gpl-3.0-with-autoconf-exception":  {License: "GPL-3.0-only", Exception: "Autoconf-exception-3.0"},
+	"gpl-3.0-with-gcc-exception":       {License: "GPL-3.0-only", Exception: "GCC-exception-3.1"},
+	"ecos-2.0":                         {License: "GPL-2.0-or-later", Exception: "eCos-exception-2.0"},
+	"wxwindows":                        {License: "LGPL-2.0-or-later", Exception: "WxWindows-exception-3.1"},
+	"nunit":                            {License: "zlib-acknowledgement"},
+	"standardml-nj":                    {License: "SMLNJ"},
+	"bzip2-1.0.5":                      {License: "bzip2-1.0.6"},
+	"bsd-2-clause-freebsd":             {License: "BSD-2-Clause"},
+	"bsd-2-clause-netbsd":              {License: "BSD-2-Clause"},
+}
+
+// Replacement records a deprecated license of an expression along with the license it was replaced with.
+type Replacement struct {
+	Original    string
+	Replacement string
+}
+
+// ReplaceDeprecatedLicense returns the license that replaces a deprecated SPDX license identifier (e.g. "LGPL-2.1+").
+// It returns false if the identifier is not deprecated.
+func ReplaceDeprecatedLicense(licenseId string) (Expression, bool) {
+	replacement, found := deprecatedLicenses[strings.ToLower(strings.TrimSpace(licenseId))]
+	return replacement, found
+}
+
+// ReplaceDeprecated returns a copy of the expression in which every deprecated license is replaced by its current equivalent,
+// along with the replacements that were made. The exception of a deprecated license is kept, unless its replacement
+// already carries an exception, in which case the license is left as it is.
+func (e Expression) ReplaceDeprecated() (Expression, []Replacement) {
+	replacements := []Replacement{}
+	replaced := e.MapLeaves(func(leaf Expression) Expression {
+		licenseId := leaf.License
+		if leaf.OrLater {
+			licenseId += "+"
+		}
+		replacement, found := ReplaceDeprecatedLicense(licenseId)
+		if !found || (leaf.Exception != "" && replacement.Exception != "") {
+			return leaf
+		}
+		if replacement.Exception == "" {
+			replacement.Exception = leaf.Exception
+		}
+		replacements = append(replacements, Replacement{Original: leaf.String(), Replacement: replacement.String()})
+		return replacement
+	})
+	return replaced, replacements
+}
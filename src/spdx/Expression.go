@@ -0,0 +1,134 @@
+package spdx
+
+import (
+	"slices"
+	"strings"
+)
+
+type Operator string
+
+const (
+	OPERATOR_AND Operator = "AND"
+	OPERATOR_OR  Operator = "OR"
+)
+
+// Expression is a node of a parsed SPDX license expression.
+// A leaf node carries a license identifier (with its optional "+" and WITH exception),
+// a compound node carries an operator and the terms it combines.
+type Expression struct {
+	Operator  Operator     `json:"operator,omitempty"`
+	Terms     []Expression `json:"terms,omitempty"`
+	License   string       `json:"license,omitempty"`
+	OrLater   bool         `json:"or_later,omitempty"`
+	Exception string       `json:"exception,omitempty"`
+}
+
+// IsLeaf reports whether the expression is a single license (optionally with an exception).
+func (e Expression) IsLeaf() bool {
+	return e.Operator == ""
+}
+
+// IsEmpty reports whether the expression holds no license at all.
+func (e Expression) IsEmpty() bool {
+	return e.IsLeaf() && e.License == ""
+}
+
+// Licenses returns the unique license identifiers referenced by the expression, in order of appearance.
+// The "+" operator and exceptions are not part of the returned identifiers.
+func (e Expression) Licenses() []string {
+	licenses := []string{}
+	e.walk(func(leaf Expression) {
+		if leaf.License != "" && !slices.Contains(licenses, leaf.License) {
+			licenses = append(licenses, leaf.License)
+		}
+	})
+	return licenses
+}
+
+// Leaves returns every leaf of the expression, in order of appearance.
+func (e Expression) Leaves() []Expression {
+	leaves := []Expression{}
+	e.walk(func(leaf Expression) {
+		leaves = append(leaves, leaf)
+	})
+	return leaves
+}
+
+// String formats the expression back into its canonical SPDX form.
+// Compound terms nested inside an operator of a different kind are wrapped in parentheses.
+func (e Expression) String() string {
+	if e.IsLeaf() {
+		var builder strings.Builder
+		builder.WriteString(e.License)
+		if e.OrLater {
+			builder.WriteString("+")
+		}
+		if e.Exception != "" {
+			builder.WriteString(" WITH ")
+			builder.WriteString(e.Exception)
+		}
+		return builder.String()
+	}
+
+	terms := make([]string, 0, len(e.Terms))
+	for _, term := range e.Terms {
+		if !term.IsLeaf() && term.Operator != e.Operator {
+			terms = append(terms, "("+term.String()+")")
+		} else {
+			terms = append(terms, term.String())
+		}
+	}
+	return strings.Join(terms, " "+string(e.Operator)+" ")
+}
+
+// MapLeaves returns a copy of the expression in which every leaf is replaced by the result of transform.
+func (e Expression) MapLeaves(transform func(leaf Expression) Expression) Expression {
+	if e.IsLeaf() {
+		return transform(e)
+	}
+
+	terms := make([]Expression, 0, len(e.Terms))
+	for _, term := range e.Terms {
+		terms = append(terms, term.MapLeaves(transform))
+	}
+	return Join(e.Operator, terms...)
+}
+
+func (e Expression) walk(visit func(leaf Expression)) {
+	if e.IsLeaf() {
+		visit(e)
+		return
+	}
+	for _, term := range e.Terms {
+		term.walk(visit)
+	}
+}
+
+// NewLicense creates a leaf expression for the given license identifier.
+func NewLicense(licenseId string) Expression {
+	return Expression{License: licenseId}
+}
+
+// Join combines the given expressions with the operator.
+// Terms that already use the same operator are flattened into the result.
+func Join(operator Operator, terms ...Expression) Expression {
+	flattened := []Expression{}
+	for _, term := range terms {
+		if term.IsEmpty() {
+			continue
+		}
+		if term.Operator == operator {
+			flattened = append(flattened, term.Terms...)
+		} else {
+			flattened = append(flattened, term)
+		}
+	}
+
+	if len(flattened) == 0 {
+		return Expression{}
+	}
+	if len(flattened) == 1 {
+		return flattened[0]
+	}
+	return Expression{Operator: operator, Terms: flattened}
+}
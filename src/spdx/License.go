@@ -0,0 +1,118 @@
+package spdx
+
+import (
+	"cmp"
+	"strconv"
+	"strings"
+)
+
+// License is a license of an expression broken down into its name, version range and exception,
+// e.g. "GPL-2.0-or-later WITH Classpath-exception-2.0" into GPL, 2.0, or later and Classpath-exception-2.0.
+type License struct {
+	Name string
+	// Version is empty for licenses without version, such as MIT or BSD-3-Clause
+	Version string
+	// OrLater is set on the "-or-later" and "+" forms, that also grant the later versions of the license
+	OrLater   bool
+	Exception string
+}
+
+// laterVersions lists, from the oldest to the latest, the versions of the licenses whose "or later" form is commonly used.
+// The "or later" form of other licenses only grants the version it names, the later ones being unknown.
+var laterVersions = map[string][]string{
+	"GPL":  {"1.0", "2.0", "3.0"},
+	"LGPL": {"2.0", "2.1", "3.0"},
+	"AGPL": {"1.0", "3.0"},
+	"GFDL": {"1.1", "1.2", "1.3"},
+}
+
+// ParseLicense breaks a leaf of an expression down into its name, version range and exception.
+func ParseLicense(leaf Expression) License {
+	license := License{Exception: leaf.Exception, OrLater: leaf.OrLater}
+
+	id := leaf.License
+	switch {
+	case strings.HasSuffix(id, "-or-later"):
+		id, license.OrLater = strings.TrimSuffix(id, "-or-later"), true
+	case strings.HasSuffix(id, "-only"):
+		id = strings.TrimSuffix(id, "-only")
+	}
+
+	index := strings.LastIndex(id, "-")
+	if index > 0 && isVersion(id[index+1:]) {
+		license.Name, license.Version = id[:index], id[index+1:]
+	} else {
+		license.Name = id
+	}
+	return license
+}
+
+// Contains reports whether a version of a license is in the version range of l, exceptions aside.
+// The "or later" form of a license contains its version and every later one, other forms only contain their own version.
+func (l License) Contains(other License) bool {
+	if !strings.EqualFold(l.Name, other.Name) {
+		return false
+	}
+	if l.OrLater && l.Version != "" && other.Version != "" {
+		return compareVersions(other.Version, l.Version) >= 0
+	}
+	return l.Version == other.Version
+}
+
+// Versions returns the single versions of the license the range of l grants, from its own version to the latest one,
+// as leaves that keep the exception of l. The "or later" form of the licenses listed in laterVersions grants every
+// later version, written in its "-only" form (e.g. "GPL-3.0-only"), any other form only grants itself.
+func (l License) Versions() []Expression {
+	own := Expression{License: l.Name, Exception: l.Exception}
+	if l.Version != "" {
+		own.License += "-" + l.Version
+	}
+
+	versions, found := laterVersions[l.Name]
+	if !l.OrLater || !found {
+		if found {
+			own.License += "-only"
+		}
+		return []Expression{own}
+	}
+
+	leaves := []Expression{}
+	for _, version := range versions {
+		if compareVersions(version, l.Version) >= 0 {
+			leaves = append(leaves, Expression{License: l.Name + "-" + version + "-only", Exception: l.Exception})
+		}
+	}
+	if len(leaves) == 0 {
+		own.License += "-only"
+		return []Expression{own}
+	}
+	return leaves
+}
+
+// isVersion reports whether a part of a license identifier is a version, such as "2.0" or "3".
+func isVersion(value string) bool {
+	for _, part := range strings.Split(value, ".") {
+		if _, err := strconv.Atoi(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// compareVersions compares two dotted versions part by part, missing parts counting as 0.
+func compareVersions(a string, b string) int {
+	partsA, partsB := strings.Split(a, "."), strings.Split(b, ".")
+	for index := 0; index < max(len(partsA), len(partsB)); index++ {
+		numberA, numberB := 0, 0
+		if index < len(partsA) {
+			numberA, _ = strconv.Atoi(partsA[index])
+		}
+		if index < len(partsB) {
+			numberB, _ = strconv.Atoi(partsB[index])
+		}
+		if result := cmp.Compare(numberA, numberB); result != 0 {
+			return result
+		}
+	}
+	return 0
+}
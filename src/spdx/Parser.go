@@ -0,0 +1,216 @@
+package spdx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidExpression is returned when a string is not a valid SPDX license expression.
+var ErrInvalidExpression = errors.New("invalid SPDX license expression")
+
+type tokenKind int
+
+const (
+	tokenLicense tokenKind = iota
+	tokenAnd
+	tokenOr
+	tokenWith
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+}
+
+// Parse parses an SPDX license expression as defined in annex D of the SPDX specification.
+// It supports the AND, OR and WITH operators, the "+" suffix and parentheses.
+// Operators bind, from tightest to loosest: WITH, AND, OR.
+// Operators are matched case-insensitively, since many package registries do not enforce the upper case form.
+func Parse(expression string) (Expression, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return Expression{}, err
+	}
+	if len(tokens) == 0 {
+		return Expression{}, fmt.Errorf("%w: empty expression", ErrInvalidExpression)
+	}
+
+	parser := parser{tokens: tokens, input: expression}
+	parsed, err := parser.parseOr()
+	if err != nil {
+		return Expression{}, err
+	}
+	if !parser.done() {
+		return Expression{}, parser.errorf("unexpected %q", parser.peek().value)
+	}
+	return parsed, nil
+}
+
+func tokenize(expression string) ([]token, error) {
+	tokens := []token{}
+	current := strings.Builder{}
+
+	flush := func() {
+		if current.Len() == 0 {
+			return
+		}
+		word := current.String()
+		current.Reset()
+		switch strings.ToUpper(word) {
+		case "AND":
+			tokens = append(tokens, token{kind: tokenAnd, value: word})
+		case "OR":
+			tokens = append(tokens, token{kind: tokenOr, value: word})
+		case "WITH":
+			tokens = append(tokens, token{kind: tokenWith, value: word})
+		default:
+			tokens = append(tokens, token{kind: tokenLicense, value: word})
+		}
+	}
+
+	for _, char := range expression {
+		switch {
+		case char == '(':
+			flush()
+			tokens = append(tokens, token{kind: tokenOpen, value: "("})
+		case char == ')':
+			flush()
+			tokens = append(tokens, token{kind: tokenClose, value: ")"})
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			flush()
+		case isIdChar(char) || char == '+' || char == ':':
+			current.WriteRune(char)
+		default:
+			return nil, fmt.Errorf("%w: invalid character %q in %q", ErrInvalidExpression, char, expression)
+		}
+	}
+	flush()
+
+	return tokens, nil
+}
+
+func isIdChar(char rune) bool {
+	return (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9') || char == '-' || char == '.'
+}
+
+type parser struct {
+	tokens   []token
+	position int
+	input    string
+}
+
+func (p *parser) done() bool {
+	return p.position >= len(p.tokens)
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.position]
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("%w: %s in %q", ErrInvalidExpression, fmt.Sprintf(format, args...), p.input)
+}
+
+// parseOr parses: or-expression = and-expression *("OR" and-expression)
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return Expression{}, err
+	}
+	terms := []Expression{left}
+	for !p.done() && p.peek().kind == tokenOr {
+		p.position++
+		right, err := p.parseAnd()
+		if err != nil {
+			return Expression{}, err
+		}
+		terms = append(terms, right)
+	}
+	return Join(OPERATOR_OR, terms...), nil
+}
+
+// parseAnd parses: and-expression = term *("AND" term)
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return Expression{}, err
+	}
+	terms := []Expression{left}
+	for !p.done() && p.peek().kind == tokenAnd {
+		p.position++
+		right, err := p.parseTerm()
+		if err != nil {
+			return Expression{}, err
+		}
+		terms = append(terms, right)
+	}
+	return Join(OPERATOR_AND, terms...), nil
+}
+
+// parseTerm parses: term = "(" or-expression ")" / simple-expression ["WITH" exception-id]
+func (p *parser) parseTerm() (Expression, error) {
+	if p.done() {
+		return Expression{}, p.errorf("unexpected end of expression")
+	}
+
+	next := p.peek()
+	switch next.kind {
+	case tokenOpen:
+		p.position++
+		inner, err := p.parseOr()
+		if err != nil {
+			return Expression{}, err
+		}
+		if p.done() || p.peek().kind != tokenClose {
+			return Expression{}, p.errorf("missing closing parenthesis")
+		}
+		p.position++
+		return inner, nil
+	case tokenLicense:
+		p.position++
+		leaf, err := p.parseLicense(next.value)
+		if err != nil {
+			return Expression{}, err
+		}
+		if !p.done() && p.peek().kind == tokenWith {
+			p.position++
+			if p.done() || p.peek().kind != tokenLicense {
+				return Expression{}, p.errorf("missing exception after WITH")
+			}
+			exception := p.peek().value
+			if strings.ContainsAny(exception, "+:") {
+				return Expression{}, p.errorf("invalid exception %q", exception)
+			}
+			leaf.Exception = exception
+			p.position++
+		}
+		return leaf, nil
+	default:
+		return Expression{}, p.errorf("unexpected %q", next.value)
+	}
+}
+
+func (p *parser) parseLicense(value string) (Expression, error) {
+	leaf := Expression{}
+	if strings.HasSuffix(value, "+") {
+		leaf.OrLater = true
+		value = strings.TrimSuffix(value, "+")
+	}
+	if value == "" || strings.Contains(value, "+") {
+		return Expression{}, p.errorf("invalid license identifier %q", value)
+	}
+
+	// Only references to other documents may contain a colon: DocumentRef-x:LicenseRef-y
+	if strings.Contains(value, ":") {
+		documentRef, licenseRef, _ := strings.Cut(value, ":")
+		if !strings.HasPrefix(documentRef, "DocumentRef-") || !strings.HasPrefix(licenseRef, "LicenseRef-") {
+			return Expression{}, p.errorf("invalid license reference %q", value)
+		}
+	}
+
+	leaf.License = value
+	return leaf, nil
+}
@@ -1,15 +1,25 @@
 package types
 
 import (
+	"github.com/CodeClarityCE/plugin-sca-license/src/compatibility"
+	"github.com/CodeClarityCE/plugin-sca-license/src/policy"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
 	codeclarity "github.com/CodeClarityCE/utility-types/codeclarity_db"
 	exceptions "github.com/CodeClarityCE/utility-types/exceptions"
 )
 
 type WorkSpaceLicenseInfo struct {
-	LicensesDepMap              map[string][]string
-	NonSpdxLicensesDepMap       map[string][]string
+	LicensesDepMap        map[string][]string
+	NonSpdxLicensesDepMap map[string][]string
+	// ProprietaryDependencies are the dependencies declared as proprietary (e.g. "UNLICENSED"), which is not an SPDX license
+	ProprietaryDependencies     []string
 	LicenseComplianceViolations []string
-	DependencyInfo              map[string]DependencyInfo
+	PolicyViolations            []PolicyViolation
+	LicenseIncompatibilities    []LicenseIncompatibility
+	// ProjectLicense is the license of the project the dependency licenses are checked against, empty if it is unknown
+	ProjectLicense       string
+	ProjectLicenseSource ProjectLicenseSource
+	DependencyInfo       map[string]DependencyInfo
 }
 
 type AnalysisStatus string
@@ -20,14 +30,157 @@ const (
 )
 
 type DependencyInfo struct {
+	Name            string
+	Version         string
 	Licenses        []string
 	NonSpdxLicenses []string
+	// Proprietary is set when the dependency is declared as proprietary, which is reported apart from Licenses and NonSpdxLicenses
+	Proprietary      bool
+	DeclaredLicense  string
+	Expression       spdx.Expression
+	LicenseFallback  bool
+	Normalizations   []LicenseNormalization
+	UnresolvedReason UnresolvedReason
+	ResolutionStatus ResolutionStatus
+	DataSource       DataSource
+	// Confidence rates the resolution of the licenses from 0 (nothing resolved) to 1 (valid SPDX identifiers)
+	Confidence float64
+	// Dev, Optional and Bundled are the flags of the dependency in the SBOM
+	Dev      bool
+	Optional bool
+	Bundled  bool
+	// Scope tells whether the dependency is shipped or only needed for development
+	Scope DependencyScope
+	// Direct tells whether the dependency is a direct dependency of the workspace
+	Direct        bool
+	PolicyVerdict PolicyVerdict
+	// PolicyDecisions are the licenses the policy verdict depends on, with the rule of the policy each one matched
+	PolicyDecisions []policy.Decision
+	// ElectedLicense is the alternative of the license expression the dependency is used under, set when the expression offers a choice
+	ElectedLicense string
+	// Compatibility tells whether the licenses of the dependency are compatible with the project license,
+	// nil when the project license is unknown or the dependency is not shipped with the project
+	Compatibility *compatibility.Verdict
+}
+
+// DataSource tells where the license information of a dependency comes from
+type DataSource string
+
+const (
+	DATA_SOURCE_KNOWLEDGE_BASE DataSource = "knowledge_base"
+	DATA_SOURCE_SBOM           DataSource = "sbom"
+)
+
+// PolicyVerdict tells whether the licenses of a dependency comply with the license policy
+type PolicyVerdict string
+
+const (
+	POLICY_VERDICT_COMPLIANT PolicyVerdict = "compliant"
+	POLICY_VERDICT_VIOLATION PolicyVerdict = "violation"
+	// Part of the licenses could not be resolved, so compliance cannot be decided
+	POLICY_VERDICT_UNKNOWN PolicyVerdict = "unknown"
+	// Every violation of the dependency is approved by a waiver
+	POLICY_VERDICT_WAIVED PolicyVerdict = "waived"
+	// The dependency is out of the scope of the policy, e.g. a dev dependency when only runtime dependencies are evaluated
+	POLICY_VERDICT_EXCLUDED PolicyVerdict = "excluded"
+)
+
+// DependencyScope tells whether a dependency is shipped with the project or only needed for development
+type DependencyScope string
+
+const (
+	SCOPE_RUNTIME DependencyScope = "runtime"
+	SCOPE_DEV     DependencyScope = "dev"
+)
+
+// PolicyViolation is a license of a dependency that the license policy denies
+type PolicyViolation struct {
+	Dependency string
+	License    string
+	// Expression is the full disjunction of a violation without any satisfying choice, whose License is that disjunction as well
+	Expression string
+	Rule       policy.Rule
+	Severity   policy.Severity
+	Status     ViolationStatus
+	Scope      DependencyScope
+	Direct     bool
+	// IntroducedBy holds the shortest paths from a direct dependency to the dependency, e.g. "express@4.18.2 -> qs@6.11.0"
+	IntroducedBy []string
+	// Waiver is the waiver matching the violation, if any. An expired waiver leaves the violation active.
+	Waiver *policy.Waiver
+}
+
+// ViolationStatus tells whether a policy violation counts against the analysis
+type ViolationStatus string
+
+const (
+	VIOLATION_STATUS_ACTIVE ViolationStatus = "active"
+	// The violation is approved by a waiver, it is reported but does not count against the analysis
+	VIOLATION_STATUS_WAIVED ViolationStatus = "waived"
+)
+
+// LicenseIncompatibility is a license of a dependency that cannot be distributed as part of the project under the project license
+type LicenseIncompatibility struct {
+	Dependency     string
+	License        string
+	ProjectLicense string
+	Reason         string
+	Severity       policy.Severity
+	Direct         bool
+	// IntroducedBy holds the shortest paths from a direct dependency to the dependency, e.g. "express@4.18.2 -> qs@6.11.0"
+	IntroducedBy []string
+}
+
+// ProjectLicenseSource tells where the license of the project comes from
+type ProjectLicenseSource string
+
+const (
+	PROJECT_LICENSE_SOURCE_CONFIG ProjectLicenseSource = "config"
+	// The project license is not configured, compatibility is not checked
+	PROJECT_LICENSE_SOURCE_NONE ProjectLicenseSource = "none"
+)
+
+// ResolutionStatus tells whether the license of a dependency could be resolved to SPDX licenses
+type ResolutionStatus string
+
+const (
+	RESOLUTION_STATUS_RESOLVED           ResolutionStatus = "resolved"
+	RESOLUTION_STATUS_PARTIALLY_RESOLVED ResolutionStatus = "partially_resolved"
+	RESOLUTION_STATUS_UNRESOLVED         ResolutionStatus = "unresolved"
+	// The license could not be looked up, the knowledge base failed to answer
+	RESOLUTION_STATUS_ERROR ResolutionStatus = "error"
+)
+
+// UnresolvedReason tells why (part of) the license of a dependency could not be resolved to SPDX licenses
+type UnresolvedReason string
+
+const (
+	UNRESOLVED_REASON_PACKAGE_NOT_FOUND UnresolvedReason = "package_not_found"
+	UNRESOLVED_REASON_LICENSE_EMPTY     UnresolvedReason = "license_empty"
+	UNRESOLVED_REASON_NON_SPDX_LICENSE  UnresolvedReason = "non_spdx_license"
+	UNRESOLVED_REASON_DATABASE_ERROR    UnresolvedReason = "database_error"
+	// The dependency refers to a license file (e.g. "SEE LICENSE IN LICENSE.md") whose text is unavailable or matches no SPDX license
+	UNRESOLVED_REASON_LICENSE_FILE UnresolvedReason = "license_file"
+)
+
+// LicenseNormalization records how a non-SPDX license identifier was mapped onto an SPDX license identifier
+type LicenseNormalization struct {
+	Original   string
+	LicenseID  string
+	Confidence float64
+	Method     string
 }
 
 type WorkSpaceLicenseInfoInternal struct {
-	LicensesDepMap              map[string][]string
-	NonSpdxLicensesDepMap       map[string][]string
+	LicensesDepMap        map[string][]string
+	NonSpdxLicensesDepMap map[string][]string
+	// ProprietaryDependencies are the dependencies declared as proprietary (e.g. "UNLICENSED"), which is not an SPDX license
+	ProprietaryDependencies     []string
 	LicenseComplianceViolations map[string][]string
+	PolicyViolations            []PolicyViolation
+	LicenseIncompatibilities    []LicenseIncompatibility
+	ProjectLicense              string
+	ProjectLicenseSource        ProjectLicenseSource
 	DependencyInfo              map[string]DependencyInfo
 }
 
@@ -36,7 +189,13 @@ type AnalysisStats struct {
 	NumberOfNonSpdxLicenses    int                             `json:"number_of_non_spdx_licenses"`
 	NumberOfCopyLeftLicenses   int                             `json:"number_of_copy_left_licenses"`
 	NumberOfPermissiveLicenses int                             `json:"number_of_permissive_licenses"`
+	NumberOfLookupErrors       int                             `json:"number_of_lookup_errors"`
+	NumberOfProprietaryDeps    int                             `json:"number_of_proprietary_dependencies"`
 	LicenseDist                AnalysisStatLicenseSeverityDist `json:"license_dist"`
+	LicenseCategoryDist        AnalysisStatLicenseCategoryDist `json:"license_category_dist"`
+	PolicyViolationDist        AnalysisStatPolicyViolationDist `json:"policy_violation_dist"`
+	NumberOfWaivedViolations   int                             `json:"number_of_waived_violations"`
+	LicenseIncompatibilityDist AnalysisStatPolicyViolationDist `json:"license_incompatibility_dist"`
 }
 
 type AnalysisInfo struct {
@@ -50,6 +209,28 @@ type AnalysisInfo struct {
 	DefaultWorkspaceName     string                     `json:"default_workspace_name"`
 	SelfManagedWorkspaceName string                     `json:"self_managed_workspace_name"`
 	AnalysisStats            AnalysisStats              `json:"stats"`
+	CacheStats               CacheStats                 `json:"cache_stats"`
+	Verdict                  AnalysisVerdict            `json:"verdict"`
+	// DeprecatedLicensesVersion is the version of the SPDX license list deprecated license identifiers were replaced according to
+	DeprecatedLicensesVersion string `json:"deprecated_licenses_version"`
+}
+
+// AnalysisVerdict tells whether the analyzed project passes the license policy
+type AnalysisVerdict string
+
+const (
+	// No violation of the policy is blocking
+	VERDICT_PASS AnalysisVerdict = "pass"
+	// At least one violation of the policy or license incompatibility is blocking, or the analysis failed
+	VERDICT_FAIL AnalysisVerdict = "fail"
+)
+
+// CacheStats tells how many dependency lookups of the analysis were answered by the license cache
+type CacheStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	// Entries is the number of dependencies held by the cache at the end of the analysis
+	Entries int `json:"entries"`
 }
 
 type Output struct {
@@ -59,6 +240,12 @@ type Output struct {
 
 type AnalysisStatLicenseSeverityDist map[string]int
 
+// AnalysisStatLicenseCategoryDist maps license categories onto the number of dependencies licensed under them
+type AnalysisStatLicenseCategoryDist map[string]int
+
+// AnalysisStatPolicyViolationDist maps severities onto the number of active policy violations, or of license incompatibilities, of that severity
+type AnalysisStatPolicyViolationDist map[string]int
+
 func ConvertOutputToMap(output Output) map[string]interface{} {
 	result := make(map[string]interface{})
 
@@ -68,7 +255,12 @@ func ConvertOutputToMap(output Output) map[string]interface{} {
 		workspace := make(map[string]interface{})
 		workspace["LicensesDepMap"] = value.LicensesDepMap
 		workspace["NonSpdxLicensesDepMap"] = value.NonSpdxLicensesDepMap
+		workspace["ProprietaryDependencies"] = value.ProprietaryDependencies
 		workspace["LicenseComplianceViolations"] = value.LicenseComplianceViolations
+		workspace["PolicyViolations"] = value.PolicyViolations
+		workspace["LicenseIncompatibilities"] = value.LicenseIncompatibilities
+		workspace["ProjectLicense"] = value.ProjectLicense
+		workspace["ProjectLicenseSource"] = value.ProjectLicenseSource
 		workspace["DependencyInfo"] = value.DependencyInfo
 		workspaces[key] = workspace
 	}
@@ -86,6 +278,9 @@ func ConvertOutputToMap(output Output) map[string]interface{} {
 	analysisInfo["default_workspace_name"] = output.AnalysisInfo.DefaultWorkspaceName
 	analysisInfo["self_managed_workspace_name"] = output.AnalysisInfo.SelfManagedWorkspaceName
 	analysisInfo["stats"] = output.AnalysisInfo.AnalysisStats
+	analysisInfo["cache_stats"] = output.AnalysisInfo.CacheStats
+	analysisInfo["verdict"] = output.AnalysisInfo.Verdict
+	analysisInfo["deprecated_licenses_version"] = output.AnalysisInfo.DeprecatedLicensesVersion
 	result["analysis_info"] = analysisInfo
 
 	return result
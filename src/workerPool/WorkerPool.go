@@ -0,0 +1,40 @@
+package workerPool
+
+import "sync"
+
+// Run calls task once for every index in [0, count), with at most concurrency tasks running at once,
+// and returns when every task has returned.
+// Tasks are started in index order. Writing the result of task i at index i of a slice
+// keeps the results in a deterministic order, whatever the order tasks complete in.
+func Run(count int, concurrency int, task func(index int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	concurrency = min(concurrency, count)
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				task(index)
+			}
+		}()
+	}
+
+	for index := range count {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+}
+
+// Split divides a concurrency budget between two nested pools, the outer one running count tasks,
+// so that at most concurrency inner tasks run at once across every outer task.
+// It returns the concurrency of the outer pool and of every inner pool.
+func Split(concurrency int, count int) (int, int) {
+	outer := max(min(concurrency, count), 1)
+	return outer, max(concurrency/outer, 1)
+}
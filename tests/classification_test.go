@@ -0,0 +1,110 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/classification"
+	"github.com/CodeClarityCE/plugin-sca-license/src/outputGenerator"
+	licenseRepository "github.com/CodeClarityCE/plugin-sca-license/src/repository/license"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+	"github.com/CodeClarityCE/plugin-sca-license/src/types"
+	knowledge "github.com/CodeClarityCE/utility-types/knowledge_db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyLicenses(t *testing.T) {
+	cases := map[string]classification.Category{
+		"MIT":                              classification.CATEGORY_PERMISSIVE,
+		"BSD-3-Clause":                     classification.CATEGORY_PERMISSIVE,
+		"Apache-2.0":                       classification.CATEGORY_PERMISSIVE,
+		"CC0-1.0":                          classification.CATEGORY_PUBLIC_DOMAIN,
+		"LGPL-2.1-or-later":                classification.CATEGORY_WEAK_COPYLEFT,
+		"MPL-2.0":                          classification.CATEGORY_WEAK_COPYLEFT,
+		"GPL-2.0-only":                     classification.CATEGORY_STRONG_COPYLEFT,
+		"GPL-3.0+":                         classification.CATEGORY_STRONG_COPYLEFT,
+		"AGPL-3.0-or-later":                classification.CATEGORY_NETWORK_COPYLEFT,
+		"BUSL-1.1":                         classification.CATEGORY_PROPRIETARY,
+		"CC-BY-NC-SA-4.0":                  classification.CATEGORY_PROPRIETARY,
+		"CC-BY-SA-4.0":                     classification.CATEGORY_STRONG_COPYLEFT,
+		"LicenseRef-custom":                classification.CATEGORY_UNKNOWN,
+		classification.LICENSE_PROPRIETARY: classification.CATEGORY_PROPRIETARY,
+	}
+
+	for licenseId, expected := range cases {
+		assert.Equal(t, expected, classification.Classify(licenseId), licenseId)
+	}
+}
+
+func TestClassifyFallsBackOnSPDXData(t *testing.T) {
+	defer classification.LoadSPDXLicenses(nil)
+
+	osiApproved := knowledge.License{LicenseID: "Fair"}
+	osiApproved.Details.IsOsiApproved = true
+	fsfLibre := knowledge.License{LicenseID: "Zimbra-1.3"}
+	fsfLibre.Details.IsFsfLibre = true
+	nonFree := knowledge.License{LicenseID: "Abstyles"}
+
+	assert.Equal(t, classification.CATEGORY_UNKNOWN, classification.Classify("Fair"))
+
+	classification.LoadSPDXLicenses([]knowledge.License{osiApproved, fsfLibre, nonFree})
+
+	// Free licenses missing from the category table are permissive
+	assert.Equal(t, classification.CATEGORY_PERMISSIVE, classification.Classify("Fair"))
+	assert.Equal(t, classification.CATEGORY_PERMISSIVE, classification.Classify("Zimbra-1.3"))
+	assert.Equal(t, classification.CATEGORY_UNKNOWN, classification.Classify("Abstyles"))
+	// The category table takes precedence over the SPDX data
+	assert.Equal(t, classification.CATEGORY_STRONG_COPYLEFT, classification.Classify("GPL-3.0-only"))
+}
+
+func TestClassifyLicenseWithLinkingException(t *testing.T) {
+	license, err := spdx.Parse("GPL-2.0-only WITH Classpath-exception-2.0")
+
+	assert.Nil(t, err)
+	assert.Equal(t, classification.CATEGORY_WEAK_COPYLEFT, classification.ClassifyLicense(license))
+}
+
+func TestGenerateAnalysisStatsClassifiesLicenses(t *testing.T) {
+	workspaceData := map[string]types.WorkSpaceLicenseInfoInternal{
+		".": {
+			LicensesDepMap: map[string][]string{
+				"MIT":          {"a@1.0.0", "b@1.0.0"},
+				"CC0-1.0":      {"c@1.0.0"},
+				"GPL-3.0-only": {"b@1.0.0"},
+			},
+			NonSpdxLicensesDepMap: map[string][]string{
+				"Custom": {"d@1.0.0"},
+			},
+			DependencyInfo: map[string]types.DependencyInfo{
+				"a@1.0.0": {Expression: spdx.NewLicense("MIT")},
+				"b@1.0.0": {Expression: spdx.Join(spdx.OPERATOR_OR, spdx.NewLicense("MIT"), spdx.NewLicense("GPL-3.0-only"))},
+				"c@1.0.0": {Expression: spdx.NewLicense("CC0-1.0")},
+				"d@1.0.0": {DeclaredLicense: "Custom", ResolutionStatus: types.RESOLUTION_STATUS_UNRESOLVED},
+				"e@1.0.0": {Expression: spdx.NewLicense(classification.LICENSE_PROPRIETARY), Proprietary: true},
+			},
+			ProprietaryDependencies: []string{"e@1.0.0"},
+		},
+	}
+
+	stats := outputGenerator.GenerateAnalysisStats(workspaceData)
+
+	assert.Equal(t, 1, stats.NumberOfCopyLeftLicenses)
+	assert.Equal(t, 2, stats.NumberOfPermissiveLicenses)
+	assert.Equal(t, 2, stats.LicenseCategoryDist[string(classification.CATEGORY_PERMISSIVE)])
+	assert.Equal(t, 1, stats.LicenseCategoryDist[string(classification.CATEGORY_STRONG_COPYLEFT)])
+	assert.Equal(t, 1, stats.LicenseCategoryDist[string(classification.CATEGORY_PUBLIC_DOMAIN)])
+	assert.Equal(t, 1, stats.LicenseCategoryDist[string(classification.CATEGORY_UNKNOWN)])
+	assert.Equal(t, 1, stats.LicenseCategoryDist[string(classification.CATEGORY_PROPRIETARY)])
+	assert.Equal(t, 3, stats.NumberOfSpdxLicenses)
+	assert.Equal(t, 1, stats.NumberOfProprietaryDeps)
+}
+
+func TestResolveReportsProprietaryApart(t *testing.T) {
+	licenses := licenseRepository.Licenses{"MIT": knowledge.License{Name: "MIT License", LicenseID: "MIT"}}
+
+	resolved := licenses.Resolve(spdx.Join(spdx.OPERATOR_OR, spdx.NewLicense("MIT"), spdx.NewLicense(classification.LICENSE_PROPRIETARY)))
+
+	// The proprietary license is neither an SPDX license nor an unknown one
+	assert.True(t, resolved.Proprietary)
+	assert.Len(t, resolved.Licenses, 1)
+	assert.Empty(t, resolved.Unresolved)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/compatibility"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+	"github.com/stretchr/testify/assert"
+)
+
+func checkCompatibility(t *testing.T, inbound string, outbound string, unknown ...string) compatibility.Verdict {
+	parsedInbound, err := spdx.Parse(inbound)
+	assert.Nil(t, err)
+	parsedOutbound, err := spdx.Parse(outbound)
+	assert.Nil(t, err)
+	return compatibility.Check(parsedInbound, parsedOutbound, func(licenseId string) bool {
+		for _, unknownId := range unknown {
+			if unknownId == licenseId {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestCompatibilityMatrix(t *testing.T) {
+	cases := []struct {
+		inbound  string
+		outbound string
+		expected compatibility.Compatibility
+	}{
+		{"MIT", "GPL-2.0-only", compatibility.COMPATIBLE},
+		{"MIT", "Apache-2.0", compatibility.COMPATIBLE},
+		{"MIT", "BUSL-1.1", compatibility.COMPATIBLE},
+		{"0BSD", "AGPL-3.0-only", compatibility.COMPATIBLE},
+		{"Apache-2.0", "GPL-2.0-only", compatibility.INCOMPATIBLE},
+		{"Apache-2.0", "GPL-2.0-or-later", compatibility.COMPATIBLE},
+		{"Apache-2.0", "GPL-3.0-only", compatibility.COMPATIBLE},
+		{"GPL-3.0-only", "MIT", compatibility.INCOMPATIBLE},
+		{"AGPL-3.0-only", "Apache-2.0", compatibility.INCOMPATIBLE},
+		{"GPL-2.0-only", "GPL-3.0-only", compatibility.INCOMPATIBLE},
+		{"GPL-2.0-or-later", "GPL-3.0-only", compatibility.COMPATIBLE},
+		{"GPL-3.0-only", "GPL-2.0-or-later", compatibility.CONDITIONAL},
+		{"GPL-3.0-only", "AGPL-3.0-only", compatibility.COMPATIBLE},
+		{"AGPL-3.0-only", "GPL-2.0-only", compatibility.INCOMPATIBLE},
+		{"LGPL-2.1-only", "GPL-2.0-only", compatibility.COMPATIBLE},
+		{"LGPL-3.0-only", "GPL-2.0-only", compatibility.INCOMPATIBLE},
+		{"LGPL-2.1-or-later", "MIT", compatibility.CONDITIONAL},
+		{"MPL-2.0", "GPL-3.0-only", compatibility.COMPATIBLE},
+		{"MPL-2.0", "Apache-2.0", compatibility.CONDITIONAL},
+		{"EPL-2.0", "GPL-2.0-only", compatibility.INCOMPATIBLE},
+		{"GPL-2.0-only WITH Classpath-exception-2.0", "MIT", compatibility.CONDITIONAL},
+		{"GPL-2.0-only WITH Classpath-exception-2.0", "GPL-2.0-only", compatibility.COMPATIBLE},
+		{"GPL-2.0-or-later WITH Classpath-exception-2.0", "GPL-3.0-only", compatibility.COMPATIBLE},
+		{"GPL-3.0-only WITH GCC-exception-3.1", "GPL-2.0-only", compatibility.INCOMPATIBLE},
+		{"BUSL-1.1", "MIT", compatibility.UNKNOWN},
+	}
+
+	for _, testCase := range cases {
+		verdict := checkCompatibility(t, testCase.inbound, testCase.outbound)
+		assert.Equal(t, testCase.expected, verdict.Compatibility, "%s into %s", testCase.inbound, testCase.outbound)
+		assert.NotEmpty(t, verdict.Reason)
+	}
+}
+
+func TestCompatibilityExpressions(t *testing.T) {
+	// The dependency may be used under its compatible alternative
+	verdict := checkCompatibility(t, "GPL-3.0-only OR MIT", "Apache-2.0")
+	assert.Equal(t, compatibility.COMPATIBLE, verdict.Compatibility)
+	assert.Equal(t, "MIT", verdict.Inbound)
+
+	// Every license of a conjunction applies
+	verdict = checkCompatibility(t, "MIT AND GPL-3.0-only", "Apache-2.0")
+	assert.Equal(t, compatibility.INCOMPATIBLE, verdict.Compatibility)
+	assert.Equal(t, "GPL-3.0-only", verdict.Inbound)
+
+	// The dependency must be compatible with every license the project may be distributed under
+	verdict = checkCompatibility(t, "Apache-2.0", "MIT OR GPL-2.0-only")
+	assert.Equal(t, compatibility.INCOMPATIBLE, verdict.Compatibility)
+	assert.Equal(t, "GPL-2.0-only", verdict.Outbound)
+}
+
+func TestCompatibilityUnknownLicenses(t *testing.T) {
+	assert.Equal(t, compatibility.UNKNOWN, checkCompatibility(t, "LicenseRef-Custom", "MIT", "LicenseRef-Custom").Compatibility)
+	assert.Equal(t, compatibility.UNKNOWN, compatibility.Check(spdx.Expression{}, spdx.NewLicense("MIT"), nil).Compatibility)
+}
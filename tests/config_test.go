@@ -0,0 +1,158 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/classification"
+	"github.com/CodeClarityCE/plugin-sca-license/src/config"
+	"github.com/CodeClarityCE/plugin-sca-license/src/policy"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConfig(t *testing.T) {
+	parsed, err := config.Parse(map[string]any{
+		"licensePolicy": []any{"GPL-3.0-only", "AGPL-3.0-only"},
+		"concurrency":   float64(8),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"GPL-3.0-only", "AGPL-3.0-only"}, parsed.LicensePolicy.DisallowedLicense)
+	assert.Equal(t, 8, parsed.Concurrency)
+}
+
+func TestParseConfigPolicyRules(t *testing.T) {
+	parsed, err := config.Parse(map[string]any{
+		"licensePolicy": []any{"AGPL-3.0-only"},
+		"licensePolicyRules": map[string]any{
+			"mode":              "allowlist",
+			"allowedLicenses":   []any{"MIT"},
+			"allowedExceptions": []any{"Classpath-exception-2.0"},
+			"deniedCategories":  []any{"strong-copyleft"},
+			"unknownLicenses":   "deny",
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, policy.MODE_ALLOWLIST, parsed.Policy.Mode)
+	assert.Equal(t, []string{"MIT"}, parsed.Policy.AllowedLicenses)
+	assert.Equal(t, []string{"Classpath-exception-2.0"}, parsed.Policy.AllowedExceptions)
+	assert.Equal(t, []classification.Category{classification.CATEGORY_STRONG_COPYLEFT}, parsed.Policy.DeniedCategories)
+	assert.Equal(t, policy.UNKNOWN_DENY, parsed.Policy.UnknownLicenses)
+	// The legacy list of disallowed licenses is denied along with the rules
+	assert.Equal(t, []string{"AGPL-3.0-only"}, parsed.EffectivePolicy().DeniedLicenses)
+}
+
+func TestParseConfigDefaults(t *testing.T) {
+	parsed, err := config.Parse(map[string]any{})
+
+	assert.Nil(t, err)
+	assert.Empty(t, parsed.LicensePolicy.DisallowedLicense)
+	assert.Equal(t, config.DEFAULT_CONCURRENCY, parsed.Concurrency)
+}
+
+func TestParseConfigRejectsInvalidOptions(t *testing.T) {
+	_, err := config.Parse(map[string]any{"licensePolicy": "MIT"})
+	assert.NotNil(t, err)
+
+	_, err = config.Parse(map[string]any{"licensePolicyRules": map[string]any{"mode": "strict"}})
+	assert.NotNil(t, err)
+
+	_, err = config.Parse(map[string]any{"licensePolicyRules": map[string]any{"deniedCategories": []any{"viral"}}})
+	assert.NotNil(t, err)
+
+	_, err = config.Parse(map[string]any{"concurrency": float64(0)})
+	assert.NotNil(t, err)
+
+	_, err = config.Parse(map[string]any{"concurrency": 2.5})
+	assert.NotNil(t, err)
+}
+
+func TestParseConfigSeverities(t *testing.T) {
+	parsed, err := config.Parse(map[string]any{
+		"licensePolicyRules": map[string]any{
+			"defaultSeverity": "warn",
+			"severities":      map[string]any{"AGPL-3.0-only": "block"},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, policy.SEVERITY_WARN, parsed.Policy.DefaultSeverity)
+	assert.Equal(t, map[string]policy.Severity{"AGPL-3.0-only": policy.SEVERITY_BLOCK}, parsed.Policy.Severities)
+
+	_, err = config.Parse(map[string]any{"licensePolicyRules": map[string]any{"severities": map[string]any{"MIT": "fatal"}}})
+	assert.NotNil(t, err)
+}
+
+func TestParseConfigWaivers(t *testing.T) {
+	parsed, err := config.Parse(map[string]any{
+		"licensePolicyRules": map[string]any{"deniedCategories": []any{"strong_copyleft"}},
+		"licenseWaivers": []any{
+			map[string]any{"package": "gulp", "versions": "^4.0.0", "justification": "Build tool, never shipped", "expires": "2026-12-31"},
+		},
+	})
+
+	assert.Nil(t, err)
+	assert.Len(t, parsed.Policy.Waivers, 1)
+	waiver := parsed.Policy.Waivers[0]
+	assert.Equal(t, "^4.0.0", waiver.Versions.String())
+	// The waiver applies until the end of its expiry date
+	assert.False(t, waiver.ExpiredAt(time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC)))
+	assert.True(t, waiver.ExpiredAt(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	_, err = config.Parse(map[string]any{"licenseWaivers": []any{map[string]any{"package": "gulp", "expires": "2026-12-31"}}})
+	assert.NotNil(t, err)
+
+	_, err = config.Parse(map[string]any{"licenseWaivers": []any{map[string]any{"package": "gulp", "justification": "Build tool", "expires": "soon"}}})
+	assert.NotNil(t, err)
+}
+
+func TestParseConfigDependencyScope(t *testing.T) {
+	parsed, err := config.Parse(map[string]any{
+		"licensePolicy":         []any{"GPL-3.0-only"},
+		"dependencyScope":       "separate",
+		"devLicensePolicyRules": map[string]any{"deniedLicenses": []any{"AGPL-3.0-only"}},
+		"licenseWaivers":        []any{map[string]any{"package": "gulp", "justification": "Build tool", "expires": "2026-12-31"}},
+	})
+
+	assert.Nil(t, err)
+	scoped := parsed.ScopedPolicy()
+	assert.Equal(t, policy.SCOPE_MODE_SEPARATE, scoped.Mode)
+	assert.Equal(t, []string{"GPL-3.0-only"}, scoped.Runtime.DeniedLicenses)
+	assert.Equal(t, []string{"AGPL-3.0-only"}, scoped.Dev.DeniedLicenses)
+	// Waivers apply to dev dependencies as well
+	assert.Len(t, scoped.Dev.Waivers, 1)
+
+	_, err = config.Parse(map[string]any{"dependencyScope": "production"})
+	assert.NotNil(t, err)
+}
+
+func TestParseConfigSeparateScopeWithoutDevRules(t *testing.T) {
+	parsed, err := config.Parse(map[string]any{
+		"licensePolicy":      []any{"GPL-3.0-only"},
+		"dependencyScope":    "separate",
+		"licensePolicyRules": map[string]any{"deniedCategories": []any{"network_copyleft"}},
+	})
+
+	assert.Nil(t, err)
+	scoped := parsed.ScopedPolicy()
+	assert.Equal(t, policy.SCOPE_MODE_SEPARATE, scoped.Mode)
+	// Without dev rules, dev dependencies are evaluated against the runtime rules and the legacy disallowed licenses
+	assert.Equal(t, []string{"GPL-3.0-only"}, scoped.Dev.DeniedLicenses)
+	assert.Equal(t, []classification.Category{classification.CATEGORY_NETWORK_COPYLEFT}, scoped.Dev.DeniedCategories)
+
+	dev, evaluated := scoped.For(true)
+	assert.True(t, evaluated)
+	assert.Equal(t, policy.OUTCOME_DENIED, dev.Evaluate(spdx.NewLicense("GPL-3.0-only"), func(string) bool { return true }).Outcome)
+}
+
+func TestParseConfigProjectLicense(t *testing.T) {
+	parsed, err := config.Parse(map[string]any{"projectLicense": "GPL-2.0-only OR MIT"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "GPL-2.0-only OR MIT", parsed.ProjectLicense.String())
+
+	_, err = config.Parse(map[string]any{"projectLicense": "MIT AND"})
+	assert.NotNil(t, err)
+}
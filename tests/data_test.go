@@ -13,3 +13,12 @@ func getmockSBOM() sbomTypes.Output {
 	json.Unmarshal([]byte(jsonSBOM), &sbom)
 	return sbom
 }
+
+// getmockComposerSBOM returns the SBOM of a composer project, whose license arrays are copied from composer.lock
+func getmockComposerSBOM() sbomTypes.Output {
+	jsonSBOM := `{"workspaces": {".": {"start": {"dependencies": [{"name": "symfony/console", "version": "v6.4.1", "constraint": "^6.4"}, {"name": "ezyang/htmlpurifier", "version": "v4.17.0", "constraint": "^4.17"}, {"name": "acme/billing-sdk", "version": "2.3.0", "constraint": "^2.3"}], "dev_dependencies": null}, "dependencies": {"symfony/console": {"v6.4.1": {"Dev": false, "Scoped": false, "Bundled": false, "Optional": false, "Requires": {"symfony/polyfill-mbstring": "~1.0"}, "Dependencies": [], "Licenses": ["MIT"]}}, "symfony/polyfill-mbstring": {"v1.28.0": {"Dev": false, "Scoped": false, "Bundled": false, "Optional": false, "Requires": null, "Dependencies": [], "Licenses": ["MIT"]}}, "ezyang/htmlpurifier": {"v4.17.0": {"Dev": false, "Scoped": false, "Bundled": false, "Optional": false, "Requires": null, "Dependencies": [], "Licenses": ["LGPL-2.1-or-later"]}}, "acme/billing-sdk": {"2.3.0": {"Dev": false, "Scoped": false, "Bundled": false, "Optional": false, "Requires": {"acme/dual-licensed": "^1.0"}, "Dependencies": [], "Licenses": ["proprietary"]}}, "acme/dual-licensed": {"1.0.4": {"Dev": false, "Scoped": false, "Bundled": false, "Optional": false, "Requires": null, "Dependencies": [], "Licenses": ["GPL-3.0-or-later", "MIT"]}}}}}, "analysis_info": {"status": "success", "project_name": "acme/shop", "errors": [], "extra": {"version_seperator": "@", "import_path_seperator": " -> "}}}`
+
+	var sbom sbomTypes.Output
+	json.Unmarshal([]byte(jsonSBOM), &sbom)
+	return sbom
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	sbomTypes "github.com/CodeClarityCE/plugin-sbom-javascript/src/types/sbom/js"
+	"github.com/CodeClarityCE/plugin-sca-license/src/dependencyGraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDependencyGraphShortestPaths(t *testing.T) {
+	workspace := sbomTypes.WorkSpace{
+		Start: sbomTypes.Start{
+			Dependencies:    []sbomTypes.WorkSpaceDependency{{Name: "express", Constraint: "^4.18.0"}, {Name: "body-parser", Constraint: "^1.20.0"}},
+			DevDependencies: []sbomTypes.WorkSpaceDependency{{Name: "grunt", Constraint: "1.5.3"}},
+		},
+		Dependencies: map[string]map[string]sbomTypes.Versions{
+			"express":      {"4.18.2": {Requires: map[string]string{"body-parser": "1.20.1", "qs": "6.11.0"}}},
+			"body-parser":  {"1.20.1": {Requires: map[string]string{"qs": "6.11.0"}}},
+			"qs":           {"6.11.0": {Requires: map[string]string{"side-channel": "^1.0.4"}}, "6.5.3": {}},
+			"side-channel": {"1.0.4": {}, "1.0.6": {}},
+			"grunt":        {"1.5.3": {}},
+			"orphan":       {"1.0.0": {}},
+		},
+	}
+
+	graph := dependencyGraph.New(workspace)
+
+	assert.True(t, graph.IsDirect(dependencyGraph.Node{Name: "express", Version: "4.18.2"}))
+	assert.True(t, graph.IsDirect(dependencyGraph.Node{Name: "grunt", Version: "1.5.3"}))
+	assert.False(t, graph.IsDirect(dependencyGraph.Node{Name: "qs", Version: "6.11.0"}))
+
+	// qs is required by both direct dependencies, the highest version satisfying a range is used
+	paths := graph.ShortestPaths(dependencyGraph.Node{Name: "side-channel", Version: "1.0.6"}, 3)
+	assert.Equal(t, [][]dependencyGraph.Node{
+		{{Name: "body-parser", Version: "1.20.1"}, {Name: "qs", Version: "6.11.0"}, {Name: "side-channel", Version: "1.0.6"}},
+		{{Name: "express", Version: "4.18.2"}, {Name: "qs", Version: "6.11.0"}, {Name: "side-channel", Version: "1.0.6"}},
+	}, paths)
+	assert.Len(t, graph.ShortestPaths(dependencyGraph.Node{Name: "side-channel", Version: "1.0.6"}, 1), 1)
+
+	assert.Equal(t, [][]dependencyGraph.Node{{{Name: "express", Version: "4.18.2"}}}, graph.ShortestPaths(dependencyGraph.Node{Name: "express", Version: "4.18.2"}, 3))
+	assert.Empty(t, graph.ShortestPaths(dependencyGraph.Node{Name: "orphan", Version: "1.0.0"}, 3))
+	assert.Empty(t, graph.ShortestPaths(dependencyGraph.Node{Name: "side-channel", Version: "1.0.4"}, 3))
+}
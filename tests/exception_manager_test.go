@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/exceptionManager"
+	"github.com/CodeClarityCE/utility-types/exceptions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorsAreCollectedPerAnalysis(t *testing.T) {
+	first := exceptionManager.NewErrors()
+	second := exceptionManager.NewErrors()
+
+	first.Append(exceptions.Error{}, exceptions.Error{})
+
+	assert.Len(t, first.GetErrors(), 2)
+	assert.Empty(t, second.GetErrors())
+	assert.Empty(t, exceptionManager.NewErrors().GetErrors())
+}
+
+func TestAddErrorFromConcurrentAnalyses(t *testing.T) {
+	analyses := []*exceptionManager.Errors{}
+	for range 8 {
+		analyses = append(analyses, exceptionManager.NewErrors())
+	}
+
+	var wait sync.WaitGroup
+	for index, analysisErrors := range analyses {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for count := range 50 {
+				message := fmt.Sprintf("analysis %d error %d", index, count)
+				analysisErrors.AddError(message, exceptions.GENERIC_ERROR, message, exceptions.GENERIC_ERROR)
+			}
+		}()
+	}
+	wait.Wait()
+
+	// Every analysis reports its own errors only, in the order it recorded them
+	for index, analysisErrors := range analyses {
+		errors := analysisErrors.GetErrors()
+		assert.Len(t, errors, 50)
+		for count, err := range errors {
+			assert.Equal(t, fmt.Sprintf("analysis %d error %d", index, count), err.Public.Description)
+			assert.Equal(t, exceptions.GENERIC_ERROR, err.Private.Type)
+		}
+	}
+}
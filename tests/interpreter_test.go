@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	sbomTypes "github.com/CodeClarityCE/plugin-sbom-javascript/src/types/sbom/js"
+	"github.com/CodeClarityCE/plugin-sca-license/src/ecosystem"
+	matcher "github.com/CodeClarityCE/plugin-sca-license/src/licenseMatcher"
+	"github.com/CodeClarityCE/plugin-sca-license/src/licenseMatcher/interpreter"
+	licenseRepository "github.com/CodeClarityCE/plugin-sca-license/src/repository/license"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+	knowledge "github.com/CodeClarityCE/utility-types/knowledge_db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNpmInterpreter(t *testing.T) {
+	npm := interpreter.For(ecosystem.ECOSYSTEM_NPM)
+
+	// The entries of the legacy licenses array are alternatives
+	assert.Equal(t, spdx.OPERATOR_OR, npm.ArrayOperator)
+
+	assert.True(t, npm.IsProprietary("UNLICENSED"))
+	assert.True(t, npm.IsProprietary("unlicensed"))
+	assert.False(t, npm.IsProprietary("Unlicense"))
+
+	tests := []struct {
+		declared string
+		file     string
+		found    bool
+	}{
+		{"SEE LICENSE IN LICENSE.md", "LICENSE.md", true},
+		{"see licence in ./docs/EULA.txt", "./docs/EULA.txt", true},
+		{"SEE LICENSE IN", "", false},
+		{"MIT", "", false},
+	}
+	for _, test := range tests {
+		file, found := npm.LicenseFile(test.declared)
+		assert.Equal(t, test.found, found, test.declared)
+		assert.Equal(t, test.file, file, test.declared)
+	}
+}
+
+func TestDefaultInterpreter(t *testing.T) {
+	unknown := interpreter.For(ecosystem.Ecosystem("unknown"))
+
+	assert.Equal(t, spdx.OPERATOR_AND, unknown.ArrayOperator)
+	assert.False(t, unknown.IsProprietary("UNLICENSED"))
+	_, found := unknown.LicenseFile("SEE LICENSE IN LICENSE.md")
+	assert.False(t, found)
+}
+
+func TestComposerInterpreter(t *testing.T) {
+	composer := interpreter.For(ecosystem.ECOSYSTEM_PACKAGIST)
+
+	// The entries of the license array are alternatives
+	assert.Equal(t, spdx.OPERATOR_OR, composer.ArrayOperator)
+
+	assert.True(t, composer.IsProprietary("proprietary"))
+	assert.False(t, composer.IsProprietary("UNLICENSED"))
+	_, found := composer.LicenseFile("SEE LICENSE IN LICENSE.md")
+	assert.False(t, found)
+}
+
+func TestLicenseFileMatchesLicenseText(t *testing.T) {
+	licenseMatcher := matcher.LicenseMatcher{LicenseDataSource: matcher.LICENSE_DATA_SOURCE_DB, PostProcessLicenses: true, Ecosystem: ecosystem.ECOSYSTEM_NPM}
+	mit, isc := knowledge.License{LicenseID: "MIT"}, knowledge.License{LicenseID: "ISC"}
+	mit.Details.LicenseText, mit.Details.StandardLicenseTemplate = mitText, mitTemplate
+	isc.Details.LicenseText = iscText
+	licenseMatcher.LoadSPDXLicenses([]knowledge.License{mit, isc})
+
+	// The knowledge base stores the text of the license file along with the declared license
+	declared := licenseRepository.VersionLicense(knowledge.Version{Extra: map[string]any{
+		"license":                            "SEE LICENSE IN LICENSE",
+		licenseRepository.LICENSE_TEXT_FIELD: strings.ReplaceAll(iscText, "<year> <copyright holders>", "2020 Some Author"),
+	}})
+	expression, normalizations, err := licenseMatcher.ParseLicenses(declared.Entries, declared.LicenseText)
+	assert.Nil(t, err)
+	assert.Equal(t, "ISC", expression.String())
+	assert.Equal(t, "SEE LICENSE IN LICENSE", normalizations[0].Original)
+
+	// Without the text of the file, the license cannot be identified
+	_, _, err = licenseMatcher.ParseLicenses([]string{"SEE LICENSE IN LICENSE"}, "")
+	assert.ErrorIs(t, err, interpreter.ErrLicenseFile)
+	// Nor when the text is not a license
+	_, _, err = licenseMatcher.ParseLicenses([]string{"SEE LICENSE IN LICENSE"}, "All rights reserved, do not copy.")
+	assert.ErrorIs(t, err, interpreter.ErrLicenseFile)
+}
+
+func TestLicenseFileWithoutTextIsUnresolved(t *testing.T) {
+	licenseMatcher := matcher.LicenseMatcher{LicenseDataSource: matcher.LICENSE_DATA_SOURCE_SBOM, Ecosystem: ecosystem.ECOSYSTEM_NPM}
+
+	// The SBOM does not carry the text of license files, so the license they declare cannot be identified
+	_, _, err := licenseMatcher.ParseSBOMLicenses(sbomTypes.Versions{Licenses: []string{"SEE LICENSE IN LICENSE.md"}})
+	assert.ErrorIs(t, err, interpreter.ErrLicenseFile)
+}
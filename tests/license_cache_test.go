@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/ecosystem"
+	"github.com/CodeClarityCE/plugin-sca-license/src/licenseCache"
+	licenseRepository "github.com/CodeClarityCE/plugin-sca-license/src/repository/license"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLicenseCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := licenseCache.NewLicenseCache(2, time.Hour)
+	ms := licenseCache.Key{Ecosystem: ecosystem.ECOSYSTEM_NPM, Name: "ms", Version: "2.1.3"}
+	qs := licenseCache.Key{Ecosystem: ecosystem.ECOSYSTEM_NPM, Name: "qs", Version: "6.11.0"}
+	lodash := licenseCache.Key{Ecosystem: ecosystem.ECOSYSTEM_NPM, Name: "lodash", Version: "4.17.21"}
+
+	cache.Set(ms, licenseRepository.DeclaredLicense{Declared: "MIT"})
+	cache.Set(qs, licenseRepository.DeclaredLicense{Declared: "BSD-3-Clause"})
+	_, found := cache.Get(ms)
+	assert.True(t, found)
+	cache.Set(lodash, licenseRepository.DeclaredLicense{Declared: "MIT"})
+
+	_, found = cache.Get(qs)
+	assert.False(t, found)
+	declared, found := cache.Get(ms)
+	assert.True(t, found)
+	assert.Equal(t, "MIT", declared.Declared)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestLicenseCacheKeysOnEcosystem(t *testing.T) {
+	cache := licenseCache.NewLicenseCache(10, time.Hour)
+
+	cache.Set(licenseCache.Key{Ecosystem: ecosystem.ECOSYSTEM_NPM, Name: "monolog", Version: "1.0.0"}, licenseRepository.DeclaredLicense{Declared: "ISC"})
+
+	_, found := cache.Get(licenseCache.Key{Ecosystem: ecosystem.ECOSYSTEM_PACKAGIST, Name: "monolog", Version: "1.0.0"})
+	assert.False(t, found)
+}
+
+func TestLicenseCacheExpiresEntries(t *testing.T) {
+	cache := licenseCache.NewLicenseCache(10, time.Millisecond)
+	key := licenseCache.Key{Ecosystem: ecosystem.ECOSYSTEM_NPM, Name: "ms", Version: "2.1.3"}
+
+	cache.Set(key, licenseRepository.DeclaredLicense{Declared: "MIT"})
+	time.Sleep(5 * time.Millisecond)
+
+	_, found := cache.Get(key)
+	assert.False(t, found)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestLicenseCacheRefreshOnKnowledgeBaseChange(t *testing.T) {
+	cache := licenseCache.NewLicenseCache(10, time.Hour)
+	key := licenseCache.Key{Ecosystem: ecosystem.ECOSYSTEM_NPM, Name: "ms", Version: "2.1.3"}
+
+	assert.True(t, cache.Refresh("package:10:0:0"))
+	cache.Set(key, licenseRepository.DeclaredLicense{Declared: "MIT"})
+
+	assert.False(t, cache.Refresh("package:10:0:0"))
+	assert.Equal(t, 1, cache.Len())
+
+	assert.True(t, cache.Refresh("package:20:0:0"))
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestLicenseCacheAnswersCachedLookups(t *testing.T) {
+	cache := licenseCache.NewLicenseCache(10, time.Hour)
+	dependency := licenseRepository.Dependency{Name: "ms", Version: "2.1.3"}
+	cache.Set(licenseCache.Key{Ecosystem: ecosystem.ECOSYSTEM_NPM, Name: "ms", Version: "2.1.3"}, licenseRepository.DeclaredLicense{Declared: "MIT"})
+
+	// Every dependency is cached, so the knowledge base is not queried
+	recorder := &licenseCache.Recorder{}
+	declaredLicenses, err := cache.GetDeclaredLicenses(nil, ecosystem.ECOSYSTEM_NPM, []licenseRepository.Dependency{dependency}, recorder)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "MIT", declaredLicenses[dependency].Declared)
+	assert.Equal(t, licenseCache.Stats{Hits: 1, Misses: 0}, recorder.Stats())
+}
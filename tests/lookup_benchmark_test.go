@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/config"
+	"github.com/CodeClarityCE/plugin-sca-license/src/ecosystem"
+	matcher "github.com/CodeClarityCE/plugin-sca-license/src/licenseMatcher"
+	licenseRepository "github.com/CodeClarityCE/plugin-sca-license/src/repository/license"
+	"github.com/CodeClarityCE/utility-boilerplates"
+	knowledge "github.com/CodeClarityCE/utility-types/knowledge_db"
+	"github.com/stretchr/testify/assert"
+	"github.com/uptrace/bun"
+)
+
+// mockSBOMDependencies lists every name@version of every workspace of the mock SBOM.
+func mockSBOMDependencies() []licenseRepository.Dependency {
+	dependencies := []licenseRepository.Dependency{}
+	for _, workspace := range getmockSBOM().WorkSpaces {
+		for name, versions := range workspace.Dependencies {
+			for version := range versions {
+				dependencies = append(dependencies, licenseRepository.Dependency{Name: name, Version: version})
+			}
+		}
+	}
+	return dependencies
+}
+
+func setTestDatabaseEnvironment() {
+	os.Setenv("PG_DB_HOST", "127.0.0.1")
+	os.Setenv("PG_DB_PORT", "5432")
+	os.Setenv("PG_DB_USER", "postgres")
+	os.Setenv("PG_DB_PASSWORD", "!ChangeMe!")
+}
+
+// legacyDependencyLicense looks the license of a dependency up the way licenses used to be looked up,
+// with a query for its package followed by a query for the license the package declares.
+func legacyDependencyLicense(knowledge_db *bun.DB, name string) (knowledge.License, error) {
+	var dependency knowledge.Package
+	err := knowledge_db.NewSelect().Model(&dependency).Where("name = ?", name).Scan(context.Background())
+	if err != nil {
+		return knowledge.License{}, err
+	}
+
+	var license knowledge.License
+	err = knowledge_db.NewSelect().Model(&license).Where("\"licenseId\" = ?", dependency.License).Scan(context.Background())
+	return license, err
+}
+
+// BenchmarkPerDependencyLookup resolves the dependencies of the mock SBOM one at a time, the way licenses used to be looked up.
+func BenchmarkPerDependencyLookup(b *testing.B) {
+	setTestDatabaseEnvironment()
+
+	pluginBase, err := boilerplates.CreatePluginBase()
+	if err != nil {
+		b.Skipf("Skipping benchmark due to database connection error: %v", err)
+		return
+	}
+	defer pluginBase.Close()
+
+	dependencies := mockSBOMDependencies()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, dependency := range dependencies {
+			// Packages without a known license are expected, only the cost of the queries is measured
+			_, _ = legacyDependencyLicense(pluginBase.DB.Knowledge, dependency.Name)
+		}
+	}
+}
+
+// BenchmarkBulkLookup resolves the dependencies of the mock SBOM the way the license matcher does, with batched queries.
+func BenchmarkBulkLookup(b *testing.B) {
+	setTestDatabaseEnvironment()
+
+	pluginBase, err := boilerplates.CreatePluginBase()
+	if err != nil {
+		b.Skipf("Skipping benchmark due to database connection error: %v", err)
+		return
+	}
+	defer pluginBase.Close()
+
+	sbom := getmockSBOM()
+	licenseMatcher := matcher.LicenseMatcher{
+		LicenseDataSource: matcher.LICENSE_DATA_SOURCE_DB,
+		Ecosystem:         ecosystem.ECOSYSTEM_NPM,
+	}
+	licensePolicy := config.Default().ScopedPolicy()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for workspaceName, workspace := range sbom.WorkSpaces {
+			info := licenseMatcher.GetWorkSpaceLicenses(pluginBase.DB.Knowledge, workspaceName, workspace, licensePolicy)
+			assert.NotEmpty(b, info.DependencyInfo)
+		}
+	}
+}
@@ -2,13 +2,20 @@ package main
 
 import (
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	license "github.com/CodeClarityCE/plugin-sca-license/src"
+	"github.com/CodeClarityCE/plugin-sca-license/src/classification"
+	"github.com/CodeClarityCE/plugin-sca-license/src/config"
+	"github.com/CodeClarityCE/plugin-sca-license/src/ecosystem"
+	matcher "github.com/CodeClarityCE/plugin-sca-license/src/licenseMatcher"
+	"github.com/CodeClarityCE/plugin-sca-license/src/licenseMatcher/interpreter"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+	"github.com/CodeClarityCE/plugin-sca-license/src/types"
 	"github.com/CodeClarityCE/utility-boilerplates"
 	codeclarity "github.com/CodeClarityCE/utility-types/codeclarity_db"
-	knowledge "github.com/CodeClarityCE/utility-types/knowledge_db"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -27,17 +34,145 @@ func TestCreate(t *testing.T) {
 	}
 	defer pluginBase.Close()
 
-	var licensePolicy = knowledge.LicensePolicy{}
-	licensePolicy.DisallowedLicense = []string{"MIT"}
+	licenseConfig := config.Default()
+	licenseConfig.LicensePolicy.DisallowedLicense = []string{"MIT"}
 
 	sbom := getmockSBOM()
 
-	out := license.Start(pluginBase.DB.Knowledge, sbom, "JS", licensePolicy, time.Now())
+	out := license.Start(pluginBase.DB.Knowledge, sbom, "JS", licenseConfig, nil, time.Now())
 
 	// Assert the expected values
 	assert.NotNil(t, out)
 	assert.Equal(t, codeclarity.SUCCESS, out.AnalysisInfo.Status)
 	assert.NotEmpty(t, out.WorkSpaces)
+
+	// Every dependency of the SBOM has its own entry
+	for workspaceName, workspace := range out.WorkSpaces {
+		versions := 0
+		for _, dependencyVersions := range sbom.WorkSpaces[workspaceName].Dependencies {
+			versions += len(dependencyVersions)
+		}
+		assert.Len(t, workspace.DependencyInfo, versions)
+		for key, info := range workspace.DependencyInfo {
+			assert.NotEmpty(t, info.Name, key)
+			assert.NotEmpty(t, info.PolicyVerdict, key)
+			assert.Equal(t, types.DATA_SOURCE_KNOWLEDGE_BASE, info.DataSource, key)
+		}
+	}
+}
+
+func TestComposerLicenses(t *testing.T) {
+	// Set test database environment
+	os.Setenv("PG_DB_HOST", "127.0.0.1")
+	os.Setenv("PG_DB_PORT", "5432")
+	os.Setenv("PG_DB_USER", "postgres")
+	os.Setenv("PG_DB_PASSWORD", "!ChangeMe!")
+
+	// Create PluginBase for testing
+	pluginBase, err := boilerplates.CreatePluginBase()
+	if err != nil {
+		t.Skipf("Skipping test due to database connection error: %v", err)
+		return
+	}
+	defer pluginBase.Close()
+
+	out := license.Start(pluginBase.DB.Knowledge, getmockComposerSBOM(), "PHP", config.Default(), nil, time.Now())
+	assert.Equal(t, codeclarity.SUCCESS, out.AnalysisInfo.Status)
+
+	dependencyInfo := out.WorkSpaces["."].DependencyInfo
+	packagist := ecosystem.ECOSYSTEM_PACKAGIST
+
+	// The entries of a composer license array are alternatives
+	dualLicensed := dependencyInfo[packagist.DependencyKey("acme/dual-licensed", "1.0.4")]
+	assert.Equal(t, "GPL-3.0-or-later OR MIT", dualLicensed.Expression.String())
+	assert.Equal(t, types.DATA_SOURCE_SBOM, dualLicensed.DataSource)
+	assert.Equal(t, "MIT", dualLicensed.ElectedLicense)
+
+	// Closed-source packages are classified as proprietary
+	proprietary := dependencyInfo[packagist.DependencyKey("acme/billing-sdk", "2.3.0")]
+	assert.Equal(t, classification.LICENSE_PROPRIETARY, proprietary.Expression.String())
+	assert.Equal(t, types.RESOLUTION_STATUS_RESOLVED, proprietary.ResolutionStatus)
+	assert.True(t, proprietary.Proprietary)
+	assert.Empty(t, proprietary.Licenses)
+	assert.Equal(t, 1, out.AnalysisInfo.AnalysisStats.NumberOfProprietaryDeps)
+	assert.Equal(t, []types.LicenseNormalization{{Original: "proprietary", LicenseID: classification.LICENSE_PROPRIETARY, Confidence: 1, Method: interpreter.METHOD_PROPRIETARY}}, proprietary.Normalizations)
+	assert.Equal(t, 1, out.AnalysisInfo.AnalysisStats.LicenseCategoryDist[string(classification.CATEGORY_PROPRIETARY)])
+
+	assert.Equal(t, "LGPL-2.1-or-later", dependencyInfo[packagist.DependencyKey("ezyang/htmlpurifier", "v4.17.0")].Expression.String())
+}
+
+// TestProjectLicense checks that the project license comes from the configuration only, and that its absence is reported.
+func TestProjectLicense(t *testing.T) {
+	// Set test database environment
+	os.Setenv("PG_DB_HOST", "127.0.0.1")
+	os.Setenv("PG_DB_PORT", "5432")
+	os.Setenv("PG_DB_USER", "postgres")
+	os.Setenv("PG_DB_PASSWORD", "!ChangeMe!")
+
+	// Create PluginBase for testing
+	pluginBase, err := boilerplates.CreatePluginBase()
+	if err != nil {
+		t.Skipf("Skipping test due to database connection error: %v", err)
+		return
+	}
+	defer pluginBase.Close()
+
+	reported := func(out types.Output) bool {
+		for _, analysisError := range out.AnalysisInfo.Errors {
+			if strings.HasPrefix(analysisError.Public.Description, "The project license is not configured") {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Without a configured license, compatibility is not checked and the missing license is reported
+	out := license.Start(pluginBase.DB.Knowledge, getmockSBOM(), "JS", config.Default(), nil, time.Now())
+	assert.Equal(t, codeclarity.SUCCESS, out.AnalysisInfo.Status)
+	assert.True(t, reported(out))
+	for _, workspace := range out.WorkSpaces {
+		assert.Empty(t, workspace.ProjectLicense)
+		assert.Equal(t, types.PROJECT_LICENSE_SOURCE_NONE, workspace.ProjectLicenseSource)
+		assert.Empty(t, workspace.LicenseIncompatibilities)
+	}
+
+	licenseConfig, err := config.Parse(map[string]any{"projectLicense": "GPL-2.0-only"})
+	assert.Nil(t, err)
+	out = license.Start(pluginBase.DB.Knowledge, getmockSBOM(), "JS", licenseConfig, nil, time.Now())
+	assert.Equal(t, codeclarity.SUCCESS, out.AnalysisInfo.Status)
+	assert.False(t, reported(out))
+	for _, workspace := range out.WorkSpaces {
+		assert.Equal(t, "GPL-2.0-only", workspace.ProjectLicense)
+		assert.Equal(t, types.PROJECT_LICENSE_SOURCE_CONFIG, workspace.ProjectLicenseSource)
+	}
+}
+
+// TestComposerSBOMLicenses parses the licenses of the composer SBOM as the analysis of a PHP SBOM does, without the knowledge base.
+func TestComposerSBOMLicenses(t *testing.T) {
+	packagist, found := ecosystem.FromLanguage("PHP")
+	assert.True(t, found)
+	assert.Equal(t, spdx.OPERATOR_OR, interpreter.For(packagist).ArrayOperator)
+
+	licenseMatcher := matcher.LicenseMatcher{LicenseDataSource: matcher.LICENSE_DATA_SOURCE_SBOM, Ecosystem: packagist}
+	dependencies := getmockComposerSBOM().WorkSpaces["."].Dependencies
+
+	// The entries of a composer license array are alternatives
+	expression, normalizations, err := licenseMatcher.ParseSBOMLicenses(dependencies["acme/dual-licensed"]["1.0.4"])
+	assert.Nil(t, err)
+	assert.Equal(t, spdx.OPERATOR_OR, expression.Operator)
+	assert.Equal(t, "GPL-3.0-or-later OR MIT", expression.String())
+	assert.Empty(t, normalizations)
+
+	// Closed-source packages are declared as proprietary
+	expression, normalizations, err = licenseMatcher.ParseSBOMLicenses(dependencies["acme/billing-sdk"]["2.3.0"])
+	assert.Nil(t, err)
+	assert.Equal(t, classification.LICENSE_PROPRIETARY, expression.String())
+	assert.Equal(t, []types.LicenseNormalization{{Original: "proprietary", LicenseID: classification.LICENSE_PROPRIETARY, Confidence: 1, Method: interpreter.METHOD_PROPRIETARY}}, normalizations)
+	assert.Equal(t, classification.CATEGORY_PROPRIETARY, classification.ClassifyLicense(expression))
+
+	expression, _, err = licenseMatcher.ParseSBOMLicenses(dependencies["ezyang/htmlpurifier"]["v4.17.0"])
+	assert.Nil(t, err)
+	assert.Equal(t, "LGPL-2.1-or-later", expression.String())
 }
 
 func BenchmarkCreate(b *testing.B) {
@@ -55,12 +190,12 @@ func BenchmarkCreate(b *testing.B) {
 	}
 	defer pluginBase.Close()
 
-	var licensePolicy = knowledge.LicensePolicy{}
-	licensePolicy.DisallowedLicense = []string{"MIT"}
+	licenseConfig := config.Default()
+	licenseConfig.LicensePolicy.DisallowedLicense = []string{"MIT"}
 
 	sbom := getmockSBOM()
 
-	out := license.Start(pluginBase.DB.Knowledge, sbom, "JS", licensePolicy, time.Now())
+	out := license.Start(pluginBase.DB.Knowledge, sbom, "JS", licenseConfig, nil, time.Now())
 
 	// Assert the expected values
 	assert.NotNil(b, out)
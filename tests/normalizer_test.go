@@ -0,0 +1,100 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/licenseMatcher/normalizer"
+	"github.com/stretchr/testify/assert"
+)
+
+var spdxLicenseIds = []string{
+	"MIT", "ISC", "Apache-2.0", "BSD-2-Clause", "BSD-3-Clause", "GPL-2.0-only", "GPL-3.0-only",
+	"GPL-3.0-or-later", "LGPL-2.1-only", "MPL-2.0", "Unlicense", "CC0-1.0", "0BSD",
+}
+
+func TestNormalizeIdentifiers(t *testing.T) {
+	licenseNormalizer := normalizer.NewNormalizer(spdxLicenseIds)
+
+	tests := []struct {
+		identifier string
+		licenseId  string
+		method     normalizer.Method
+	}{
+		{"mit", "MIT", normalizer.METHOD_CASE_INSENSITIVE},
+		{"GPL-2.0", "GPL-2.0-only", normalizer.METHOD_DEPRECATED},
+		{"BSD", "BSD-2-Clause", normalizer.METHOD_ALIAS},
+		{"Apache 2", "Apache-2.0", normalizer.METHOD_ALIAS},
+		{"Apache License, Version 2.0", "Apache-2.0", normalizer.METHOD_ALIAS},
+		{"MIT/X11", "MIT", normalizer.METHOD_ALIAS},
+		{"GPLv3", "GPL-3.0-only", normalizer.METHOD_ALIAS},
+		{"The Unlicense", "Unlicense", normalizer.METHOD_ALIAS},
+		{"Apache-2", "Apache-2.0", normalizer.METHOD_ALIAS},
+		{"BSD-3-Clausee", "BSD-3-Clause", normalizer.METHOD_FUZZY},
+	}
+
+	for _, test := range tests {
+		result, ok := licenseNormalizer.Normalize(test.identifier)
+		assert.True(t, ok, test.identifier)
+		assert.Equal(t, test.licenseId, result.LicenseID, test.identifier)
+		assert.Equal(t, test.method, result.Method, test.identifier)
+		assert.Equal(t, test.identifier, result.Original, test.identifier)
+		assert.Greater(t, result.Confidence, 0.0, test.identifier)
+	}
+}
+
+func TestNormalizeUnknownIdentifiers(t *testing.T) {
+	licenseNormalizer := normalizer.NewNormalizer(spdxLicenseIds)
+
+	for _, identifier := range []string{"", "UNLICENSED", "SEE LICENSE IN LICENSE.txt", "Commercial"} {
+		_, ok := licenseNormalizer.Normalize(identifier)
+		assert.False(t, ok, identifier)
+	}
+}
+
+func TestNormalizeAmbiguousAliasHasLowerConfidence(t *testing.T) {
+	licenseNormalizer := normalizer.NewNormalizer(spdxLicenseIds)
+
+	ambiguous, _ := licenseNormalizer.Normalize("BSD")
+	explicit, _ := licenseNormalizer.Normalize("BSD 3 Clause")
+
+	assert.Greater(t, explicit.Confidence, ambiguous.Confidence)
+}
+
+func TestNormalizeOrLaterAliases(t *testing.T) {
+	licenseNormalizer := normalizer.NewNormalizer(append(spdxLicenseIds, "GPL-2.0-or-later", "LGPL-2.1-or-later", "AGPL-3.0-only", "AGPL-3.0-or-later"))
+
+	tests := []struct {
+		identifier string
+		licenseId  string
+	}{
+		{"GPLv3+", "GPL-3.0-or-later"},
+		{"GPL-2+", "GPL-2.0-or-later"},
+		{"LGPLv2.1+", "LGPL-2.1-or-later"},
+		{"AGPL-3.0+", "AGPL-3.0-or-later"},
+		{"GPL v2 or later", "GPL-2.0-or-later"},
+		{"GNU GPL v3 or any later version", "GPL-3.0-or-later"},
+		{"GPLv3", "GPL-3.0-only"},
+	}
+
+	for _, test := range tests {
+		result, ok := licenseNormalizer.Normalize(test.identifier)
+		assert.True(t, ok, test.identifier)
+		assert.Equal(t, test.licenseId, result.LicenseID, test.identifier)
+		assert.Equal(t, normalizer.METHOD_ALIAS, result.Method, test.identifier)
+	}
+}
+
+func TestNormalizeFuzzyKeepsVersions(t *testing.T) {
+	licenseNormalizer := normalizer.NewNormalizer(append(spdxLicenseIds, "Apache-1.1", "CC-BY-NC-SA-4.0"))
+
+	// Unknown versions are never matched onto another version
+	for _, identifier := range []string{"Apache-2.1", "CC-BY-NC-SA-4.1", "BSD-4-Clausee"} {
+		_, ok := licenseNormalizer.Normalize(identifier)
+		assert.False(t, ok, identifier)
+	}
+
+	result, ok := licenseNormalizer.Normalize("CC-BY-NC-SAA-4.0")
+	assert.True(t, ok)
+	assert.Equal(t, "CC-BY-NC-SA-4.0", result.LicenseID)
+	assert.Equal(t, normalizer.METHOD_FUZZY, result.Method)
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/classification"
+	"github.com/CodeClarityCE/plugin-sca-license/src/policy"
+	"github.com/CodeClarityCE/plugin-sca-license/src/semver"
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+	"github.com/stretchr/testify/assert"
+)
+
+func evaluate(t *testing.T, licensePolicy policy.Policy, expression string, unknown ...string) policy.Result {
+	parsed, err := spdx.Parse(expression)
+	assert.Nil(t, err)
+	return licensePolicy.Evaluate(parsed, func(licenseId string) bool {
+		for _, unknownId := range unknown {
+			if unknownId == licenseId {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func TestPolicyDenylist(t *testing.T) {
+	licensePolicy := policy.Default()
+	licensePolicy.DeniedLicenses = []string{"GPL-3.0-only"}
+
+	result := evaluate(t, licensePolicy, "MIT AND GPL-3.0-only")
+	assert.Equal(t, policy.OUTCOME_DENIED, result.Outcome)
+	assert.Equal(t, []policy.Decision{{License: "GPL-3.0-only", Outcome: policy.OUTCOME_DENIED, Rule: policy.Rule{Kind: policy.RULE_DENIED_LICENSE, Value: "GPL-3.0-only"}, Severity: policy.SEVERITY_BLOCK}}, result.Decisions)
+
+	result = evaluate(t, licensePolicy, "MIT")
+	assert.Equal(t, policy.OUTCOME_ALLOWED, result.Outcome)
+	assert.Equal(t, policy.RULE_NOT_DENIED, result.Decisions[0].Rule.Kind)
+}
+
+func TestPolicyAllowlist(t *testing.T) {
+	licensePolicy := policy.Default()
+	licensePolicy.Mode = policy.MODE_ALLOWLIST
+	licensePolicy.AllowedLicenses = []string{"MIT"}
+	licensePolicy.AllowedCategories = []classification.Category{classification.CATEGORY_PUBLIC_DOMAIN}
+
+	assert.Equal(t, policy.OUTCOME_ALLOWED, evaluate(t, licensePolicy, "MIT").Outcome)
+	assert.Equal(t, policy.OUTCOME_ALLOWED, evaluate(t, licensePolicy, "CC0-1.0").Outcome)
+
+	result := evaluate(t, licensePolicy, "Apache-2.0")
+	assert.Equal(t, policy.OUTCOME_DENIED, result.Outcome)
+	assert.Equal(t, "not_allowed", result.Decisions[0].Rule.String())
+}
+
+func TestPolicyCategoryRules(t *testing.T) {
+	licensePolicy := policy.Default()
+	licensePolicy.DeniedCategories = []classification.Category{classification.CATEGORY_STRONG_COPYLEFT}
+	licensePolicy.AllowedLicenses = []string{"GPL-2.0-only"}
+
+	result := evaluate(t, licensePolicy, "GPL-3.0-or-later")
+	assert.Equal(t, policy.OUTCOME_DENIED, result.Outcome)
+	assert.Equal(t, "denied_category:strong_copyleft", result.Decisions[0].Rule.String())
+
+	// Rules on licenses take precedence over rules on categories
+	assert.Equal(t, policy.OUTCOME_ALLOWED, evaluate(t, licensePolicy, "GPL-2.0-only").Outcome)
+}
+
+func TestPolicyDisjunctionPassesWithAnyAllowedAlternative(t *testing.T) {
+	licensePolicy := policy.Default()
+	licensePolicy.DeniedCategories = []classification.Category{classification.CATEGORY_STRONG_COPYLEFT}
+
+	result := evaluate(t, licensePolicy, "MIT OR GPL-3.0-only")
+	assert.Equal(t, policy.OUTCOME_ALLOWED, result.Outcome)
+	assert.Equal(t, "MIT", result.Decisions[0].License)
+
+	result = evaluate(t, licensePolicy, "GPL-2.0-only OR GPL-3.0-only")
+	assert.Equal(t, policy.OUTCOME_DENIED, result.Outcome)
+	assert.Len(t, result.Denied(), 1)
+	assert.Len(t, result.Denied()[0].Alternatives, 2)
+}
+
+func TestPolicyElectsLeastRestrictiveChoice(t *testing.T) {
+	licensePolicy := policy.Default()
+	licensePolicy.DeniedLicenses = []string{"GPL-3.0-only"}
+
+	result := evaluate(t, licensePolicy, "GPL-3.0-only OR MIT")
+	assert.Equal(t, policy.OUTCOME_ALLOWED, result.Outcome)
+	assert.Equal(t, "MIT", result.Elected.String())
+
+	// Both alternatives are allowed, the permissive one is elected
+	result = evaluate(t, licensePolicy, "(GPL-2.0-or-later AND BSD-3-Clause) OR MIT")
+	assert.Equal(t, "MIT", result.Elected.String())
+
+	// Choices nested in a conjunction are resolved on their own
+	result = evaluate(t, licensePolicy, "Apache-2.0 AND (GPL-3.0-only OR BSD-2-Clause)")
+	assert.Equal(t, "Apache-2.0 AND BSD-2-Clause", result.Elected.String())
+}
+
+func TestPolicyNoSatisfyingChoice(t *testing.T) {
+	licensePolicy := policy.Default()
+	licensePolicy.DeniedLicenses = []string{"GPL-2.0-only", "GPL-3.0-only"}
+	licensePolicy.Severities = map[string]policy.Severity{"GPL-2.0-only": policy.SEVERITY_WARN}
+
+	result := evaluate(t, licensePolicy, "MIT AND (GPL-2.0-only OR GPL-3.0-only)")
+	assert.Equal(t, policy.OUTCOME_DENIED, result.Outcome)
+	assert.True(t, result.Elected.IsEmpty())
+
+	denied := result.Denied()
+	assert.Len(t, denied, 1)
+	// The dependency is not used under either alternative, the full disjunction is reported
+	assert.Equal(t, "GPL-2.0-only OR GPL-3.0-only", denied[0].License)
+	assert.Equal(t, "GPL-2.0-only OR GPL-3.0-only", denied[0].Expression)
+	assert.Equal(t, policy.RULE_NO_SATISFYING_CHOICE, denied[0].Rule.Kind)
+	// The least severe alternative can still be chosen
+	assert.Equal(t, policy.SEVERITY_WARN, denied[0].Severity)
+	assert.Equal(t, "GPL-3.0-only", denied[0].Alternatives[1].License)
+}
+
+func TestPolicyNoSatisfyingChoiceWaivers(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	licensePolicy := policy.Default()
+	licensePolicy.DeniedLicenses = []string{"AGPL-3.0-only", "GPL-3.0-only"}
+	licensePolicy.Waivers = []policy.Waiver{
+		{Package: "ghostscript", License: "GPL-3.0-only", Justification: "Only invoked as a separate process", Expires: now.AddDate(1, 0, 0)},
+		{Package: "mongodb", License: "(AGPL-3.0-only  OR GPL-3.0-only)", Justification: "Internal tool", Expires: now.AddDate(1, 0, 0)},
+	}
+
+	result := evaluate(t, licensePolicy, "AGPL-3.0-only OR GPL-3.0-only")
+	assert.Equal(t, policy.OUTCOME_DENIED, result.Outcome)
+	denied := result.Denied()
+	assert.Len(t, denied, 1)
+	assert.Len(t, denied[0].Alternatives, 2)
+
+	// A waiver of one of the alternatives waives the disjunction, under which the dependency can then be used
+	_, found := licensePolicy.FindWaiver(".", "ghostscript", "10.0.0", denied[0].License, denied[0].Expression, now)
+	assert.True(t, found)
+	// As does a waiver of the disjunction, however it is written
+	_, found = licensePolicy.FindWaiver(".", "mongodb", "7.0.0", denied[0].License, denied[0].Expression, now)
+	assert.True(t, found)
+
+	// A waiver of a license does not waive a disjunction it is not part of
+	_, found = licensePolicy.FindWaiver(".", "ghostscript", "10.0.0", "MPL-2.0 OR AGPL-3.0-only", "MPL-2.0 OR AGPL-3.0-only", now)
+	assert.False(t, found)
+}
+
+func TestPolicyOrLaterVersions(t *testing.T) {
+	licensePolicy := policy.Default()
+	licensePolicy.Mode = policy.MODE_ALLOWLIST
+	licensePolicy.AllowedLicenses = []string{"GPL-3.0-only"}
+
+	// GPL-2.0-or-later can be used under the GPL-3.0
+	result := evaluate(t, licensePolicy, "GPL-2.0-or-later")
+	assert.Equal(t, policy.OUTCOME_ALLOWED, result.Outcome)
+	assert.Equal(t, "GPL-3.0-only", result.Elected.String())
+	assert.Equal(t, "GPL-2.0-or-later", result.Decisions[0].License)
+	assert.Equal(t, policy.OUTCOME_ALLOWED, evaluate(t, licensePolicy, "GPL-2.0+").Outcome)
+
+	assert.Equal(t, policy.OUTCOME_DENIED, evaluate(t, licensePolicy, "GPL-2.0-only").Outcome)
+	assert.Equal(t, policy.OUTCOME_DENIED, evaluate(t, licensePolicy, "LGPL-2.1-or-later").Outcome)
+
+	// A rule on an "or later" license covers the later versions
+	licensePolicy.AllowedLicenses = []string{"LGPL-2.0-or-later"}
+	assert.Equal(t, policy.OUTCOME_ALLOWED, evaluate(t, licensePolicy, "LGPL-2.1-only").Outcome)
+	assert.Equal(t, policy.OUTCOME_DENIED, evaluate(t, licensePolicy, "GPL-2.0-only").Outcome)
+
+	// Denying a single version leaves the later ones available
+	licensePolicy = policy.Default()
+	licensePolicy.DeniedLicenses = []string{"GPL-2.0-only"}
+	result = evaluate(t, licensePolicy, "GPL-2.0-or-later")
+	assert.Equal(t, policy.OUTCOME_ALLOWED, result.Outcome)
+	assert.Equal(t, "GPL-3.0-only", result.Elected.String())
+
+	// The declared version is kept when it is allowed
+	licensePolicy.DeniedLicenses = []string{"GPL-3.0-or-later"}
+	result = evaluate(t, licensePolicy, "GPL-2.0-or-later")
+	assert.Equal(t, "GPL-2.0-or-later", result.Elected.String())
+	assert.Equal(t, policy.OUTCOME_DENIED, evaluate(t, licensePolicy, "GPL-3.0+").Outcome)
+	// Deprecated identifiers without suffix are the single version they name
+	assert.Equal(t, policy.OUTCOME_DENIED, evaluate(t, licensePolicy, "GPL-3.0").Outcome)
+}
+
+func TestPolicyExceptions(t *testing.T) {
+	licensePolicy := policy.Default()
+	licensePolicy.DeniedLicenses = []string{"GPL-2.0-only"}
+
+	// Without an allowed exception, the license is denied whatever its exception
+	assert.Equal(t, policy.OUTCOME_DENIED, evaluate(t, licensePolicy, "GPL-2.0-only WITH Classpath-exception-2.0").Outcome)
+
+	licensePolicy.AllowedExceptions = []string{"Classpath-exception-2.0"}
+	result := evaluate(t, licensePolicy, "GPL-2.0-only WITH Classpath-exception-2.0")
+	assert.Equal(t, policy.OUTCOME_ALLOWED, result.Outcome)
+	assert.Equal(t, "allowed_exception:Classpath-exception-2.0", result.Decisions[0].Rule.String())
+	assert.Equal(t, policy.OUTCOME_DENIED, evaluate(t, licensePolicy, "GPL-2.0-only WITH GCC-exception-2.0").Outcome)
+	assert.Equal(t, policy.OUTCOME_DENIED, evaluate(t, licensePolicy, "GPL-2.0-only").Outcome)
+
+	// A rule that names the exception takes precedence over the allowed exceptions
+	licensePolicy.DeniedLicenses = []string{"GPL-2.0-or-later WITH Classpath-exception-2.0"}
+	result = evaluate(t, licensePolicy, "GPL-3.0-only WITH Classpath-exception-2.0")
+	assert.Equal(t, policy.OUTCOME_DENIED, result.Outcome)
+	assert.Equal(t, "denied_license:GPL-2.0-or-later WITH Classpath-exception-2.0", result.Decisions[0].Rule.String())
+
+	// Allowing a license with an exception does not allow the license without it
+	licensePolicy = policy.Default()
+	licensePolicy.Mode = policy.MODE_ALLOWLIST
+	licensePolicy.AllowedLicenses = []string{"GPL-2.0-only WITH Classpath-exception-2.0"}
+	assert.Equal(t, policy.OUTCOME_ALLOWED, evaluate(t, licensePolicy, "GPL-2.0-only WITH Classpath-exception-2.0").Outcome)
+	assert.Equal(t, policy.OUTCOME_DENIED, evaluate(t, licensePolicy, "GPL-2.0-only").Outcome)
+}
+
+func TestPolicyUnknownLicenses(t *testing.T) {
+	licensePolicy := policy.Default()
+
+	result := evaluate(t, licensePolicy, "MIT AND Custom", "Custom")
+	assert.Equal(t, policy.OUTCOME_UNKNOWN, result.Outcome)
+	assert.Equal(t, "unknown_license:Custom", result.Decisions[0].Rule.String())
+
+	// An unknown alternative does not matter when another one is allowed
+	assert.Equal(t, policy.OUTCOME_ALLOWED, evaluate(t, licensePolicy, "Custom OR MIT", "Custom").Outcome)
+
+	licensePolicy.UnknownLicenses = policy.UNKNOWN_DENY
+	assert.Equal(t, policy.OUTCOME_DENIED, evaluate(t, licensePolicy, "MIT AND Custom", "Custom").Outcome)
+	assert.Equal(t, policy.OUTCOME_DENIED, licensePolicy.EvaluateUnknown("").Outcome)
+}
+
+func TestPolicySeverities(t *testing.T) {
+	licensePolicy := policy.Default()
+	licensePolicy.DeniedLicenses = []string{"AGPL-3.0-only", "MPL-2.0"}
+	licensePolicy.DeniedCategories = []classification.Category{classification.CATEGORY_WEAK_COPYLEFT}
+	licensePolicy.Severities = map[string]policy.Severity{
+		"denied_license:MPL-2.0": policy.SEVERITY_WARN,
+		"weak_copyleft":          policy.SEVERITY_INFO,
+	}
+
+	assert.Equal(t, policy.SEVERITY_BLOCK, evaluate(t, licensePolicy, "AGPL-3.0-only").Decisions[0].Severity)
+	assert.Equal(t, policy.SEVERITY_WARN, evaluate(t, licensePolicy, "MPL-2.0").Decisions[0].Severity)
+	assert.Equal(t, policy.SEVERITY_INFO, evaluate(t, licensePolicy, "LGPL-2.1-only").Decisions[0].Severity)
+	// Allowed licenses have no severity
+	assert.Empty(t, evaluate(t, licensePolicy, "MIT").Decisions[0].Severity)
+}
+
+func TestPolicySeveritiesInAllowlistMode(t *testing.T) {
+	licensePolicy := policy.Default()
+	licensePolicy.Mode = policy.MODE_ALLOWLIST
+	licensePolicy.AllowedLicenses = []string{"MIT", "Apache-2.0"}
+	licensePolicy.Severities = map[string]policy.Severity{
+		"MPL-2.0":         policy.SEVERITY_WARN,
+		"AGPL-3.0-only":   policy.SEVERITY_BLOCK,
+		"strong_copyleft": policy.SEVERITY_BLOCK,
+	}
+	licensePolicy.DefaultSeverity = policy.SEVERITY_INFO
+
+	// Licenses that are not allowed are denied by a rule that names neither a license nor a category
+	result := evaluate(t, licensePolicy, "MPL-2.0")
+	assert.Equal(t, policy.RULE_NOT_ALLOWED, result.Decisions[0].Rule.Kind)
+	assert.Equal(t, policy.SEVERITY_WARN, result.Decisions[0].Severity)
+	assert.Equal(t, policy.SEVERITY_BLOCK, evaluate(t, licensePolicy, "AGPL-3.0-only").Decisions[0].Severity)
+	assert.Equal(t, policy.SEVERITY_BLOCK, evaluate(t, licensePolicy, "GPL-3.0-only").Decisions[0].Severity)
+	assert.Equal(t, policy.SEVERITY_INFO, evaluate(t, licensePolicy, "BSD-3-Clause").Decisions[0].Severity)
+}
+
+func TestPolicyWaivers(t *testing.T) {
+	versions, err := semver.ParseRange("^4.0.0")
+	assert.Nil(t, err)
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	licensePolicy := policy.Default()
+	licensePolicy.Waivers = []policy.Waiver{
+		{Package: "gulp", Versions: &versions, License: "GPL-3.0-only", Justification: "Build tool", Expires: now.AddDate(0, 1, 0)},
+		{Package: "jszip", Workspace: "frontend", Justification: "Legacy", Expires: now.AddDate(0, -1, 0)},
+	}
+
+	waiver, found := licensePolicy.FindWaiver(".", "gulp", "4.0.2", "GPL-3.0-only", "", now)
+	assert.True(t, found)
+	assert.False(t, waiver.ExpiredAt(now))
+
+	_, found = licensePolicy.FindWaiver(".", "gulp", "5.0.0", "GPL-3.0-only", "", now)
+	assert.False(t, found)
+	_, found = licensePolicy.FindWaiver(".", "gulp", "4.0.2", "AGPL-3.0-only", "", now)
+	assert.False(t, found)
+	_, found = licensePolicy.FindWaiver(".", "jszip", "3.10.1", "GPL-3.0-only", "", now)
+	assert.False(t, found)
+
+	// Expired waivers are still found, so that their expiry can be reported
+	waiver, found = licensePolicy.FindWaiver("frontend", "jszip", "3.10.1", "GPL-3.0-only", "", now)
+	assert.True(t, found)
+	assert.True(t, waiver.ExpiredAt(now))
+}
+
+func TestScopedPolicy(t *testing.T) {
+	runtime := policy.Default()
+	runtime.DeniedCategories = []classification.Category{classification.CATEGORY_STRONG_COPYLEFT}
+	dev := policy.Default()
+
+	scoped := policy.ScopedPolicy{Mode: policy.SCOPE_MODE_SEPARATE, Runtime: runtime, Dev: dev}
+	devPolicy, evaluated := scoped.For(true)
+	assert.True(t, evaluated)
+	assert.Equal(t, policy.OUTCOME_ALLOWED, evaluate(t, devPolicy, "GPL-3.0-only").Outcome)
+	runtimePolicy, _ := scoped.For(false)
+	assert.Equal(t, policy.OUTCOME_DENIED, evaluate(t, runtimePolicy, "GPL-3.0-only").Outcome)
+
+	scoped.Mode = policy.SCOPE_MODE_ALL
+	devPolicy, _ = scoped.For(true)
+	assert.Equal(t, policy.OUTCOME_DENIED, evaluate(t, devPolicy, "GPL-3.0-only").Outcome)
+
+	scoped.Mode = policy.SCOPE_MODE_RUNTIME
+	_, evaluated = scoped.For(true)
+	assert.False(t, evaluated)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/ecosystem"
+	"github.com/CodeClarityCE/plugin-sca-license/src/purl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatPurl(t *testing.T) {
+	assert.Equal(t, "pkg:npm/%40babel/core@7.0.0", purl.New("npm", "@babel/core", "7.0.0").String())
+	assert.Equal(t, "pkg:npm/lodash@4.17.21", purl.New("npm", "lodash", "4.17.21").String())
+	assert.Equal(t, "pkg:composer/symfony/console@6.4.0", purl.New("composer", "Symfony/Console", "6.4.0").String())
+	assert.Equal(t, "pkg:npm/semver@1.0.0%2Bbuild.1", purl.New("npm", "semver", "1.0.0+build.1").String())
+}
+
+func TestParsePurl(t *testing.T) {
+	parsed, err := purl.Parse("pkg:npm/%40babel/core@7.0.0?repository_url=https%3A%2F%2Fregistry.npmjs.org#lib/index.js")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "npm", parsed.Type)
+	assert.Equal(t, "@babel", parsed.Namespace)
+	assert.Equal(t, "core", parsed.Name)
+	assert.Equal(t, "7.0.0", parsed.Version)
+	assert.Equal(t, "https://registry.npmjs.org", parsed.Qualifiers["repository_url"])
+	assert.Equal(t, "lib/index.js", parsed.Subpath)
+	assert.Equal(t, "@babel/core", parsed.PackageName())
+}
+
+func TestParsePurlYieldsCanonicalForm(t *testing.T) {
+	parsed, err := purl.Parse("pkg://NPM/@babel/Core@7.0.0")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "pkg:npm/%40babel/core@7.0.0", parsed.String())
+}
+
+func TestParseInvalidPurl(t *testing.T) {
+	for _, value := range []string{"npm/lodash@4.17.21", "pkg:npm", "pkg:npm/lodash@%4", "pkg:/lodash"} {
+		_, err := purl.Parse(value)
+		assert.ErrorIs(t, err, purl.ErrInvalidPurl, value)
+	}
+}
+
+func TestDependencyKeysArePurls(t *testing.T) {
+	assert.Equal(t, "pkg:npm/%40babel/core@7.0.0", ecosystem.ECOSYSTEM_NPM.DependencyKey("@babel/core", "7.0.0"))
+	assert.Equal(t, "pkg:composer/monolog/monolog@3.5.0", ecosystem.ECOSYSTEM_PACKAGIST.DependencyKey("monolog/monolog", "3.5.0"))
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/semver"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	ordered := []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0", "1.0.1", "1.10.0", "2.0.0"}
+	for index := 1; index < len(ordered); index++ {
+		lower, err := semver.Parse(ordered[index-1])
+		assert.Nil(t, err)
+		higher, err := semver.Parse(ordered[index])
+		assert.Nil(t, err)
+		assert.Equal(t, -1, lower.Compare(higher), ordered[index])
+	}
+
+	withBuild, err := semver.Parse("v1.0.0+build.5")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, withBuild.Compare(semver.Version{Major: 1}))
+}
+
+func TestParseInvalidVersion(t *testing.T) {
+	for _, value := range []string{"1.0", "1.0.0-", "a.b.c", "1.0.0-alpha..1"} {
+		_, err := semver.Parse(value)
+		assert.ErrorIs(t, err, semver.ErrInvalidVersion, value)
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	cases := map[string]map[string]bool{
+		"^1.2.3":          {"1.2.3": true, "1.9.0": true, "2.0.0": false, "1.2.2": false, "2.0.0-alpha": false},
+		"^0.2.3":          {"0.2.9": true, "0.3.0": false},
+		"^0.0.3":          {"0.0.3": true, "0.0.4": false},
+		"~1.2.3":          {"1.2.9": true, "1.3.0": false},
+		"1.x":             {"1.0.0": true, "1.99.0": true, "2.0.0": false},
+		"*":               {"0.0.1": true, "3.0.0": true, "3.0.0-beta": false},
+		">= 1.0.0 <1.5":   {"1.4.9": true, "1.5.0": false, "0.9.0": false},
+		"1.0.0 - 1.2":     {"1.2.7": true, "1.3.0": false},
+		"<1.0.0 || >=3.0": {"0.5.0": true, "2.0.0": false, "3.1.0": true},
+		">1.2":            {"1.2.9": false, "1.3.0": true},
+		"<=1.2":           {"1.2.9": true, "1.3.0": false},
+		">=1.2.3-beta.2":  {"1.2.3-beta.4": true, "1.2.4-beta.1": false, "1.2.4": true},
+	}
+
+	for value, versions := range cases {
+		versionRange, err := semver.ParseRange(value)
+		assert.Nil(t, err, value)
+		for version, expected := range versions {
+			parsed, err := semver.Parse(version)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, versionRange.Contains(parsed), "%s in %s", version, value)
+		}
+	}
+}
+
+func TestParseInvalidRange(t *testing.T) {
+	for _, value := range []string{"^a.b", ">=", "1.2.3.4", "1.2-beta"} {
+		_, err := semver.ParseRange(value)
+		assert.ErrorIs(t, err, semver.ErrInvalidRange, value)
+	}
+}
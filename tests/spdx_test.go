@@ -0,0 +1,122 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/spdx"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSimpleLicense(t *testing.T) {
+	expression, err := spdx.Parse("MIT")
+
+	assert.NoError(t, err)
+	assert.True(t, expression.IsLeaf())
+	assert.Equal(t, "MIT", expression.License)
+	assert.Equal(t, []string{"MIT"}, expression.Licenses())
+}
+
+func TestParseCompoundExpressions(t *testing.T) {
+	tests := []struct {
+		input     string
+		formatted string
+		licenses  []string
+	}{
+		{"(MIT OR Apache-2.0)", "MIT OR Apache-2.0", []string{"MIT", "Apache-2.0"}},
+		{"MIT AND BSD-3-Clause OR ISC", "(MIT AND BSD-3-Clause) OR ISC", []string{"MIT", "BSD-3-Clause", "ISC"}},
+		{"MIT AND (BSD-3-Clause OR ISC)", "MIT AND (BSD-3-Clause OR ISC)", []string{"MIT", "BSD-3-Clause", "ISC"}},
+		{"GPL-2.0-only WITH Classpath-exception-2.0", "GPL-2.0-only WITH Classpath-exception-2.0", []string{"GPL-2.0-only"}},
+		{"LGPL-2.1+ OR MIT", "LGPL-2.1+ OR MIT", []string{"LGPL-2.1", "MIT"}},
+		{"mit or apache-2.0", "mit OR apache-2.0", []string{"mit", "apache-2.0"}},
+		{"((MIT))", "MIT", []string{"MIT"}},
+		{"MIT OR MIT", "MIT OR MIT", []string{"MIT"}},
+		{"DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2", "DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2", []string{"DocumentRef-spdx-tool-1.2:LicenseRef-MIT-Style-2"}},
+	}
+
+	for _, test := range tests {
+		expression, err := spdx.Parse(test.input)
+		assert.NoError(t, err, test.input)
+		assert.Equal(t, test.formatted, expression.String(), test.input)
+		assert.Equal(t, test.licenses, expression.Licenses(), test.input)
+	}
+}
+
+func TestParseOperatorPrecedence(t *testing.T) {
+	expression, err := spdx.Parse("MIT OR GPL-2.0-only WITH Classpath-exception-2.0 AND ISC")
+
+	assert.NoError(t, err)
+	assert.Equal(t, spdx.OPERATOR_OR, expression.Operator)
+	assert.Len(t, expression.Terms, 2)
+	assert.Equal(t, spdx.OPERATOR_AND, expression.Terms[1].Operator)
+	assert.Equal(t, "Classpath-exception-2.0", expression.Terms[1].Terms[0].Exception)
+}
+
+func TestParseInvalidExpressions(t *testing.T) {
+	invalid := []string{
+		"",
+		"Apache 2",
+		"MIT/X11",
+		"(MIT OR ISC",
+		"MIT OR",
+		"AND MIT",
+		"MIT WITH",
+		"MIT)",
+		"SEE LICENSE IN LICENSE.txt",
+	}
+
+	for _, input := range invalid {
+		_, err := spdx.Parse(input)
+		assert.ErrorIs(t, err, spdx.ErrInvalidExpression, input)
+	}
+}
+
+func TestParseLicenseVersionRange(t *testing.T) {
+	expression, err := spdx.Parse("GPL-2.0-or-later WITH Classpath-exception-2.0")
+	assert.NoError(t, err)
+
+	license := spdx.ParseLicense(expression)
+	assert.Equal(t, spdx.License{Name: "GPL", Version: "2.0", OrLater: true, Exception: "Classpath-exception-2.0"}, license)
+	assert.Equal(t, "GPL-2.0-only WITH Classpath-exception-2.0 OR GPL-3.0-only WITH Classpath-exception-2.0", spdx.Join(spdx.OPERATOR_OR, license.Versions()...).String())
+
+	assert.Equal(t, spdx.License{Name: "LGPL", Version: "2.1", OrLater: true}, spdx.ParseLicense(spdx.Expression{License: "LGPL-2.1", OrLater: true}))
+	assert.Equal(t, spdx.License{Name: "BSD-3-Clause"}, spdx.ParseLicense(spdx.NewLicense("BSD-3-Clause")))
+	// Later versions of other licenses are unknown
+	assert.Equal(t, []spdx.Expression{spdx.NewLicense("Apache-2.0")}, spdx.ParseLicense(spdx.Expression{License: "Apache-2.0", OrLater: true}).Versions())
+}
+
+func TestLicenseContains(t *testing.T) {
+	orLater := spdx.ParseLicense(spdx.NewLicense("GPL-2.0-or-later"))
+	assert.True(t, orLater.Contains(spdx.ParseLicense(spdx.NewLicense("GPL-3.0-only"))))
+	assert.True(t, orLater.Contains(spdx.ParseLicense(spdx.NewLicense("GPL-2.0"))))
+	assert.False(t, orLater.Contains(spdx.ParseLicense(spdx.NewLicense("GPL-1.0-only"))))
+	assert.False(t, orLater.Contains(spdx.ParseLicense(spdx.NewLicense("LGPL-3.0-only"))))
+
+	only := spdx.ParseLicense(spdx.NewLicense("GPL-2.0-only"))
+	assert.False(t, only.Contains(spdx.ParseLicense(spdx.NewLicense("GPL-3.0-only"))))
+}
+
+func TestReplaceDeprecated(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		{"GPL-2.0", "GPL-2.0-only"},
+		{"LGPL-2.1+ OR MIT", "LGPL-2.1-or-later OR MIT"},
+		{"eCos-2.0", "GPL-2.0-or-later WITH eCos-exception-2.0"},
+		{"wxWindows AND Apache-2.0", "LGPL-2.0-or-later WITH WxWindows-exception-3.1 AND Apache-2.0"},
+		{"GPL-2.0 WITH Classpath-exception-2.0", "GPL-2.0-only WITH Classpath-exception-2.0"},
+		{"MIT", "MIT"},
+	}
+
+	for _, test := range tests {
+		expression, err := spdx.Parse(test.expression)
+		assert.NoError(t, err, test.expression)
+
+		replaced, _ := expression.ReplaceDeprecated()
+		assert.Equal(t, test.expected, replaced.String(), test.expression)
+	}
+
+	expression, _ := spdx.Parse("GPL-3.0+ AND MIT")
+	_, replacements := expression.ReplaceDeprecated()
+	assert.Equal(t, []spdx.Replacement{{Original: "GPL-3.0+", Replacement: "GPL-3.0-or-later"}}, replacements)
+}
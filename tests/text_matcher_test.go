@@ -0,0 +1,97 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/licenseMatcher/textMatcher"
+	"github.com/stretchr/testify/assert"
+)
+
+const mitText = `MIT License
+
+Copyright (c) <year> <copyright holders>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+`
+
+const mitTemplate = `<<beginOptional>> MIT License<<endOptional>>
+
+<<var;name="copyright";original="Copyright (c) <year> <copyright holders>";match=".{0,5000}">>
+
+Permission is hereby granted, free of charge, to any person obtaining a copy of this software and associated documentation files (the "Software"), to deal in the Software without restriction, including without limitation the rights to use, copy, modify, merge, publish, distribute, sublicense, and/or sell copies of the Software, and to permit persons to whom the Software is furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE <<var;name="copyrightHolder2";original="AUTHORS OR COPYRIGHT HOLDERS";match=".+">> BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE SOFTWARE.
+`
+
+const iscText = `ISC License
+
+Copyright (c) <year> <copyright holders>
+
+Permission to use, copy, modify, and/or distribute this software for any purpose with or without fee is hereby granted, provided that the above copyright notice and this permission notice appear in all copies.
+
+THE SOFTWARE IS PROVIDED "AS IS" AND THE AUTHOR DISCLAIMS ALL WARRANTIES WITH REGARD TO THIS SOFTWARE INCLUDING ALL IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS. IN NO EVENT SHALL THE AUTHOR BE LIABLE FOR ANY SPECIAL, DIRECT, INDIRECT, OR CONSEQUENTIAL DAMAGES OR ANY DAMAGES WHATSOEVER RESULTING FROM LOSS OF USE, DATA OR PROFITS, WHETHER IN AN ACTION OF CONTRACT, NEGLIGENCE OR OTHER TORTIOUS ACTION, ARISING OUT OF OR IN CONNECTION WITH THE USE OR PERFORMANCE OF THIS SOFTWARE.
+`
+
+func newTestTextMatcher() *textMatcher.TextMatcher {
+	return textMatcher.NewTextMatcher([]textMatcher.Candidate{
+		{LicenseID: "MIT", Text: mitText, Template: mitTemplate},
+		{LicenseID: "ISC", Text: iscText},
+	})
+}
+
+func TestNormalizeTextFollowsMatchingGuidelines(t *testing.T) {
+	original := "Copyright (c) 2024 Jane Doe\n\n  1. Redistributions of the   Licence\n  * must be kept at http://example.com"
+	variant := "// Copyright © 2019 ACME Corp.\n// a) redistributions of the license\n// - MUST be kept at https://example.com"
+
+	assert.Equal(t, textMatcher.NormalizeText(original), textMatcher.NormalizeText(variant))
+	assert.Equal(t, "redistributions of the license must be kept at http example com", textMatcher.NormalizeText(original))
+}
+
+func TestMatchIdenticalLicenseText(t *testing.T) {
+	text := strings.ReplaceAll(mitText, "<year> <copyright holders>", "2016 Some Author")
+
+	result, ok := newTestTextMatcher().Match(text)
+
+	assert.True(t, ok)
+	assert.Equal(t, "MIT", result.LicenseID)
+	assert.Equal(t, textMatcher.METHOD_HASH, result.Method)
+	assert.Equal(t, 1.0, result.Score)
+}
+
+func TestMatchLicenseTemplate(t *testing.T) {
+	text := "Copyright (c) 2016 Some Author\n\n" + mitText[len("MIT License\n\nCopyright (c) <year> <copyright holders>\n"):]
+	text = strings.ReplaceAll(text, "AUTHORS OR COPYRIGHT HOLDERS", "CONTRIBUTORS")
+
+	result, ok := newTestTextMatcher().Match(text)
+
+	assert.True(t, ok)
+	assert.Equal(t, "MIT", result.LicenseID)
+	assert.Equal(t, textMatcher.METHOD_TEMPLATE, result.Method)
+	assert.Equal(t, 1.0, result.Score)
+}
+
+func TestMatchSlightlyAlteredLicenseText(t *testing.T) {
+	altered := strings.ReplaceAll(mitText, "free of charge", "at no cost")
+	altered = strings.ReplaceAll(altered, "substantial portions", "significant parts")
+
+	result, ok := newTestTextMatcher().Match(altered)
+
+	assert.True(t, ok)
+	assert.Equal(t, "MIT", result.LicenseID)
+	assert.Equal(t, textMatcher.METHOD_SIMILARITY, result.Method)
+	assert.Greater(t, result.Score, 0.9)
+	assert.Less(t, result.Score, 1.0)
+}
+
+func TestMatchUnrelatedText(t *testing.T) {
+	_, ok := newTestTextMatcher().Match("This software is proprietary and confidential. Unauthorized copying of this file, via any medium, is strictly prohibited.")
+
+	assert.False(t, ok)
+}
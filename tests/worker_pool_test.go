@@ -0,0 +1,60 @@
+package main
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/CodeClarityCE/plugin-sca-license/src/workerPool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerPoolRunsEveryTask(t *testing.T) {
+	results := make([]int, 50)
+
+	workerPool.Run(len(results), 4, func(index int) {
+		results[index] = index * index
+	})
+
+	for index, result := range results {
+		assert.Equal(t, index*index, result)
+	}
+}
+
+func TestWorkerPoolBoundsConcurrency(t *testing.T) {
+	var running, maxRunning atomic.Int32
+
+	workerPool.Run(20, 3, func(index int) {
+		current := running.Add(1)
+		for {
+			observed := maxRunning.Load()
+			if current <= observed || maxRunning.CompareAndSwap(observed, current) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		running.Add(-1)
+	})
+
+	assert.LessOrEqual(t, maxRunning.Load(), int32(3))
+	assert.Greater(t, maxRunning.Load(), int32(0))
+}
+
+func TestWorkerPoolSplitBoundsNestedConcurrency(t *testing.T) {
+	cases := []struct {
+		concurrency, count, outer, inner int
+	}{
+		{4, 2, 2, 2},
+		{4, 1, 1, 4},
+		{4, 8, 4, 1},
+		{5, 2, 2, 2},
+		{0, 3, 1, 1},
+	}
+
+	for _, testCase := range cases {
+		outer, inner := workerPool.Split(testCase.concurrency, testCase.count)
+		assert.Equal(t, testCase.outer, outer, "%d tasks with a concurrency of %d", testCase.count, testCase.concurrency)
+		assert.Equal(t, testCase.inner, inner, "%d tasks with a concurrency of %d", testCase.count, testCase.concurrency)
+		assert.LessOrEqual(t, outer*inner, max(testCase.concurrency, 1))
+	}
+}